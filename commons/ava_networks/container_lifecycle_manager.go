@@ -0,0 +1,69 @@
+package ava_networks
+
+import (
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// Stops, starts, pauses and unpauses containers without removing them, so they keep their filesystem and IP
+type ContainerLifecycleManager interface {
+	StopContainer(containerId string, timeout time.Duration) error
+	KillContainer(containerId string) error
+	StartContainer(containerId string) error
+	PauseContainer(containerId string) error
+	UnpauseContainer(containerId string) error
+}
+
+/*
+Manages containers through the Docker CLI
+
+NOTE: This needs the Docker CLI and access to the Docker engine, which the controller image has.
+*/
+type DockerCliContainerLifecycleManager struct{}
+
+func (manager DockerCliContainerLifecycleManager) StopContainer(containerId string, timeout time.Duration) error {
+	timeoutSeconds := strconv.Itoa(int(timeout.Seconds()))
+	if err := runDockerCommand("stop", "--time", timeoutSeconds, containerId); err != nil {
+		return stacktrace.Propagate(err, "Could not stop container %v", containerId)
+	}
+	return nil
+}
+
+func (manager DockerCliContainerLifecycleManager) KillContainer(containerId string) error {
+	if err := runDockerCommand("kill", containerId); err != nil {
+		return stacktrace.Propagate(err, "Could not kill container %v", containerId)
+	}
+	return nil
+}
+
+func (manager DockerCliContainerLifecycleManager) StartContainer(containerId string) error {
+	if err := runDockerCommand("start", containerId); err != nil {
+		return stacktrace.Propagate(err, "Could not start container %v", containerId)
+	}
+	return nil
+}
+
+func (manager DockerCliContainerLifecycleManager) PauseContainer(containerId string) error {
+	if err := runDockerCommand("pause", containerId); err != nil {
+		return stacktrace.Propagate(err, "Could not pause container %v", containerId)
+	}
+	return nil
+}
+
+func (manager DockerCliContainerLifecycleManager) UnpauseContainer(containerId string) error {
+	if err := runDockerCommand("unpause", containerId); err != nil {
+		return stacktrace.Propagate(err, "Could not unpause container %v", containerId)
+	}
+	return nil
+}
+
+func runDockerCommand(args ...string) error {
+	output, err := exec.Command(dockerBinary, args...).CombinedOutput()
+	if err != nil {
+		return stacktrace.Propagate(err, "Docker command with args %v failed with output: %v", args, string(output))
+	}
+	return nil
+}
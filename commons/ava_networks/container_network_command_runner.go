@@ -0,0 +1,57 @@
+package ava_networks
+
+import (
+	"os/exec"
+
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Image with the networking tools (iptables, tc, etc.) needed to manipulate a container's networking, which Gecko
+	//  images don't ship with
+	DEFAULT_NETWORK_TOOLS_IMAGE = "nicolaka/netshoot"
+
+	dockerBinary = "docker"
+)
+
+// Runs shell commands inside the network namespace of a container, so the commands can change the container's networking
+type ContainerNetworkCommandRunner interface {
+	RunInNetworkNamespace(containerId string, shellCommand string) error
+}
+
+/*
+Runs commands in a throwaway "sidecar" container which shares the target container's network namespace, so that the
+target container's image doesn't need any networking tools or extra privileges.
+
+NOTE: This needs the Docker CLI and access to the Docker engine, which the controller image has.
+*/
+type DockerSidecarCommandRunner struct {
+	networkToolsImage string
+}
+
+/*
+Args:
+	networkToolsImage: The image to run the sidecar containers from, which must have 'sh' and any tools that the commands
+		use (e.g. DEFAULT_NETWORK_TOOLS_IMAGE)
+*/
+func NewDockerSidecarCommandRunner(networkToolsImage string) *DockerSidecarCommandRunner {
+	return &DockerSidecarCommandRunner{networkToolsImage: networkToolsImage}
+}
+
+func (runner DockerSidecarCommandRunner) RunInNetworkNamespace(containerId string, shellCommand string) error {
+	cmd := exec.Command(
+		dockerBinary,
+		"run",
+		"--rm",
+		"--network=container:"+containerId,
+		"--cap-add=NET_ADMIN",
+		runner.networkToolsImage,
+		"sh",
+		"-c",
+		shellCommand)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return stacktrace.Propagate(err, "Command '%v' failed in network namespace of container %v with output: %v", shellCommand, containerId, string(output))
+	}
+	return nil
+}
@@ -13,6 +13,7 @@ package ava_networks
 // Genesis information about the network when running in 'local' mode, which comes from hardcodeed information in
 //  the Gecko source
 var DefaultLocalNetGenesisConfig = NetworkGenesisConfig{
+	NetworkID: "local",
 	Stakers: defaultStakers,
 	// hardcoded in Gecko in "genesis/config.go". needed to distribute genesis funds in tests
 	FundedAddresses: FundedAddress{
@@ -0,0 +1,66 @@
+package ava_networks
+
+import (
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
+	"github.com/palantir/stacktrace"
+)
+
+/*
+Builds the genesis of a 'local' network with any number of stakers, whose identities (cert, key, and node ID) are
+generated on the fly rather than hardcoded like DefaultLocalNetGenesisConfig's are.
+
+NOTE: Gecko v0.5.7 has no way to start from a custom genesis, so its 'local' network's validators are always the five
+hardcoded stakers; a built genesis therefore only gives a network its number of boot nodes, and should only be used for
+networks without staking. Generating the stakers' 4096-bit keys is slow, so a genesis should be built once and reused.
+*/
+type GenesisBuilder struct {
+	numStakers int
+}
+
+/*
+Args:
+	numStakers: The number of stakers (and therefore boot nodes) that the network will have at genesis
+*/
+func NewGenesisBuilder(numStakers int) *GenesisBuilder {
+	return &GenesisBuilder{
+		numStakers: numStakers,
+	}
+}
+
+func (builder GenesisBuilder) Build() (*NetworkGenesisConfig, error) {
+	if builder.numStakers < 1 {
+		return nil, stacktrace.NewError("A network must have at least one staker, but %v were requested", builder.numStakers)
+	}
+
+	stakers, err := generateStakers(builder.numStakers)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred generating the stakers")
+	}
+	return &NetworkGenesisConfig{
+		NetworkID: DefaultLocalNetGenesisConfig.NetworkID,
+		Stakers:   stakers,
+		// The 'local' network's funds are hardcoded in Gecko along with its genesis
+		FundedAddresses: DefaultLocalNetGenesisConfig.FundedAddresses,
+	}, nil
+}
+
+func generateStakers(numStakers int) ([]StakerIdentity, error) {
+	certProvider := cert_providers.NewRandomGeckoCertProvider(true)
+	stakers := make([]StakerIdentity, 0, numStakers)
+	for i := 0; i < numStakers; i++ {
+		nodeId, err := certProvider.GetNodeId()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred computing the node ID for staker %v", i)
+		}
+		certPem, keyPem, err := certProvider.GetCertAndKey()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred generating the cert for staker %v", i)
+		}
+		stakers = append(stakers, StakerIdentity{
+			NodeID:     nodeId,
+			PrivateKey: keyPem.String(),
+			TlsCert:    certPem.String(),
+		})
+	}
+	return stakers, nil
+}
@@ -0,0 +1,29 @@
+package ava_networks
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildGenesis(t *testing.T) {
+	genesisConfig, err := NewGenesisBuilder(3).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultLocalNetGenesisConfig.NetworkID, genesisConfig.NetworkID)
+	assert.Equal(t, DefaultLocalNetGenesisConfig.FundedAddresses, genesisConfig.FundedAddresses)
+	assert.Equal(t, 3, len(genesisConfig.Stakers))
+
+	for _, staker := range genesisConfig.Stakers {
+		nodeId, err := cert_providers.ComputeNodeId(*bytes.NewBufferString(staker.TlsCert))
+		assert.NoError(t, err)
+		assert.Equal(t, nodeId, staker.NodeID)
+	}
+	assert.NotEqual(t, genesisConfig.Stakers[0].NodeID, genesisConfig.Stakers[1].NodeID)
+}
+
+func TestBuildGenesisRequiresStakers(t *testing.T) {
+	_, err := NewGenesisBuilder(0).Build()
+	assert.Error(t, err, "A genesis without stakers should be rejected")
+}
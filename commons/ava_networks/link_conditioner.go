@@ -0,0 +1,241 @@
+package ava_networks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// The network interface that Docker connects containers to their network with
+	containerNetworkInterface = "eth0"
+
+	// HTB classes need a rate, so classes which shouldn't be rate-limited get one far above what a Docker bridge can do
+	unlimitedClassRate = "10gbit"
+
+	// The minor number of the HTB class that traffic matching no peer filter goes through
+	defaultClassMinor = 1
+)
+
+// Conditions to degrade a link with, where the zero value of each field means "not degraded"
+type LinkConditions struct {
+	// Delay added to every packet
+	Latency time.Duration
+
+	// Random variation in the delay added to each packet, in both directions from Latency
+	Jitter time.Duration
+
+	// Percentage (0-100) of packets to drop
+	PacketLossPercent float64
+
+	// Max rate to send at, in kilobits per second (0 for no limit)
+	BandwidthLimitKbps uint64
+}
+
+func (conditions LinkConditions) validate() error {
+	if conditions.Latency < 0 {
+		return stacktrace.NewError("Latency must be non-negative, but was %v", conditions.Latency)
+	}
+	if conditions.Jitter < 0 {
+		return stacktrace.NewError("Jitter must be non-negative, but was %v", conditions.Jitter)
+	}
+	if conditions.PacketLossPercent < 0 || conditions.PacketLossPercent > 100 {
+		return stacktrace.NewError("Packet loss must be between 0 and 100 percent, but was %v", conditions.PacketLossPercent)
+	}
+	return nil
+}
+
+// Gets the netem arguments which apply these conditions
+func (conditions LinkConditions) getNetemArgs() string {
+	args := []string{
+		fmt.Sprintf("delay %dus %dus", conditions.Latency.Microseconds(), conditions.Jitter.Microseconds()),
+	}
+	if conditions.PacketLossPercent > 0 {
+		args = append(args, fmt.Sprintf("loss %v%%", conditions.PacketLossPercent))
+	}
+	if conditions.BandwidthLimitKbps > 0 {
+		args = append(args, fmt.Sprintf("rate %vkbit", conditions.BandwidthLimitKbps))
+	}
+	return strings.Join(args, " ")
+}
+
+// The link conditions that a single service sends its traffic under
+type serviceLinkState struct {
+	container serviceContainer
+
+	// If non-nil, conditions for traffic to any peer which doesn't have its own conditions
+	defaultConditions *LinkConditions
+
+	// Peer IP -> conditions for traffic to that peer
+	peerConditions map[string]LinkConditions
+}
+
+func (state serviceLinkState) isEmpty() bool {
+	return state.defaultConditions == nil && len(state.peerConditions) == 0
+}
+
+/*
+Degrades the links between services with netem, applied to each service's outgoing traffic. A service's conditions are
+rebuilt from scratch on every change, so they can be changed at any point in a test.
+*/
+type linkConditioner struct {
+	commandRunner ContainerNetworkCommandRunner
+
+	// Guards serviceStates, which is shared between all copies of the network
+	mutex *sync.Mutex
+
+	// Service ID -> link conditions currently applied to the service
+	serviceStates map[networks.ServiceID]*serviceLinkState
+}
+
+func newLinkConditioner(commandRunner ContainerNetworkCommandRunner) *linkConditioner {
+	return &linkConditioner{
+		commandRunner: commandRunner,
+		mutex:         &sync.Mutex{},
+		serviceStates: make(map[networks.ServiceID]*serviceLinkState),
+	}
+}
+
+// Applies the given conditions to all traffic the service sends, except to peers with their own conditions
+func (conditioner *linkConditioner) setServiceConditions(serviceId networks.ServiceID, container serviceContainer, conditions LinkConditions) error {
+	if err := conditions.validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid link conditions for service %v", serviceId)
+	}
+
+	conditioner.mutex.Lock()
+	defer conditioner.mutex.Unlock()
+	state := conditioner.getOrCreateState(serviceId, container)
+	state.defaultConditions = &conditions
+	if err := conditioner.applyState(serviceId, *state); err != nil {
+		return stacktrace.Propagate(err, "An error occurred applying link conditions to service %v", serviceId)
+	}
+	return nil
+}
+
+// Applies the given conditions to traffic in both directions between the two services
+func (conditioner *linkConditioner) setPairConditions(
+	serviceId1 networks.ServiceID,
+	container1 serviceContainer,
+	serviceId2 networks.ServiceID,
+	container2 serviceContainer,
+	conditions LinkConditions) error {
+	if serviceId1 == serviceId2 {
+		return stacktrace.NewError("Link conditions can't be set between service %v and itself", serviceId1)
+	}
+	if err := conditions.validate(); err != nil {
+		return stacktrace.Propagate(err, "Invalid link conditions for link between services %v and %v", serviceId1, serviceId2)
+	}
+
+	conditioner.mutex.Lock()
+	defer conditioner.mutex.Unlock()
+	state1 := conditioner.getOrCreateState(serviceId1, container1)
+	state1.peerConditions[container2.ipAddr] = conditions
+	if err := conditioner.applyState(serviceId1, *state1); err != nil {
+		return stacktrace.Propagate(err, "An error occurred applying link conditions to service %v", serviceId1)
+	}
+	state2 := conditioner.getOrCreateState(serviceId2, container2)
+	state2.peerConditions[container1.ipAddr] = conditions
+	if err := conditioner.applyState(serviceId2, *state2); err != nil {
+		return stacktrace.Propagate(err, "An error occurred applying link conditions to service %v", serviceId2)
+	}
+	return nil
+}
+
+// Removes all link conditions from every service
+func (conditioner *linkConditioner) clear() error {
+	conditioner.mutex.Lock()
+	defer conditioner.mutex.Unlock()
+
+	failedServiceIds := []string{}
+	for serviceId, state := range conditioner.serviceStates {
+		emptyState := serviceLinkState{
+			container:      state.container,
+			peerConditions: make(map[string]LinkConditions),
+		}
+		if err := conditioner.applyState(serviceId, emptyState); err != nil {
+			failedServiceIds = append(failedServiceIds, string(serviceId))
+			continue
+		}
+		delete(conditioner.serviceStates, serviceId)
+	}
+	if len(failedServiceIds) > 0 {
+		sort.Strings(failedServiceIds)
+		return stacktrace.NewError("Could not remove link conditions from services: %v", strings.Join(failedServiceIds, ", "))
+	}
+	return nil
+}
+
+/*
+Stops tracking the link conditions of a service whose container has been removed, so that later changes and clearing
+don't try to run tc in the removed container. Conditions other services have towards the removed container's IP are kept.
+*/
+func (conditioner *linkConditioner) forgetService(serviceId networks.ServiceID) {
+	conditioner.mutex.Lock()
+	defer conditioner.mutex.Unlock()
+	delete(conditioner.serviceStates, serviceId)
+}
+
+func (conditioner *linkConditioner) getOrCreateState(serviceId networks.ServiceID, container serviceContainer) *serviceLinkState {
+	state, found := conditioner.serviceStates[serviceId]
+	if !found {
+		state = &serviceLinkState{
+			container:      container,
+			peerConditions: make(map[string]LinkConditions),
+		}
+		conditioner.serviceStates[serviceId] = state
+	}
+	return state
+}
+
+func (conditioner *linkConditioner) applyState(serviceId networks.ServiceID, state serviceLinkState) error {
+	if err := conditioner.commandRunner.RunInNetworkNamespace(state.container.containerId, getTcCommand(state)); err != nil {
+		return stacktrace.Propagate(err, "An error occurred running tc in the container of service %v", serviceId)
+	}
+	return nil
+}
+
+/*
+Gets a shell command which replaces a container's outgoing traffic shaping with the given state: an HTB qdisc whose
+default class carries the service's default conditions, plus one class per peer with conditions, each with a filter
+sending the traffic to that peer through it
+*/
+func getTcCommand(state serviceLinkState) string {
+	commands := []string{
+		"set -e",
+		// Deleting fails if there's no shaping yet, which is fine
+		fmt.Sprintf("tc qdisc del dev %v root 2>/dev/null || true", containerNetworkInterface),
+	}
+	if state.isEmpty() {
+		return strings.Join(commands, "\n")
+	}
+
+	commands = append(commands,
+		fmt.Sprintf("tc qdisc add dev %v root handle 1: htb default %x", containerNetworkInterface, defaultClassMinor),
+		fmt.Sprintf("tc class add dev %v parent 1: classid 1:%x htb rate %v", containerNetworkInterface, defaultClassMinor, unlimitedClassRate))
+	if state.defaultConditions != nil {
+		commands = append(commands, fmt.Sprintf(
+			"tc qdisc add dev %v parent 1:%x netem %v",
+			containerNetworkInterface,
+			defaultClassMinor,
+			state.defaultConditions.getNetemArgs()))
+	}
+
+	peerIps := []string{}
+	for peerIp := range state.peerConditions {
+		peerIps = append(peerIps, peerIp)
+	}
+	sort.Strings(peerIps)
+	for i, peerIp := range peerIps {
+		classMinor := defaultClassMinor + 1 + i
+		commands = append(commands,
+			fmt.Sprintf("tc class add dev %v parent 1: classid 1:%x htb rate %v", containerNetworkInterface, classMinor, unlimitedClassRate),
+			fmt.Sprintf("tc qdisc add dev %v parent 1:%x netem %v", containerNetworkInterface, classMinor, state.peerConditions[peerIp].getNetemArgs()),
+			fmt.Sprintf("tc filter add dev %v protocol ip parent 1: prio 1 u32 match ip dst %v/32 flowid 1:%x", containerNetworkInterface, peerIp, classMinor))
+	}
+	return strings.Join(commands, "\n")
+}
@@ -0,0 +1,106 @@
+package ava_networks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testContainer1 = serviceContainer{containerId: "container-1", ipAddr: "172.17.0.2"}
+var testContainer2 = serviceContainer{containerId: "container-2", ipAddr: "172.17.0.3"}
+
+func getLastCommand(runner *recordingCommandRunner, containerId string) string {
+	commands := runner.commands[containerId]
+	return commands[len(commands)-1]
+}
+
+func TestNetemArgs(t *testing.T) {
+	conditions := LinkConditions{
+		Latency:            100 * time.Millisecond,
+		Jitter:             10 * time.Millisecond,
+		PacketLossPercent:  2.5,
+		BandwidthLimitKbps: 1000,
+	}
+	assert.Equal(t, "delay 100000us 10000us loss 2.5% rate 1000kbit", conditions.getNetemArgs())
+	assert.Equal(t, "delay 0us 0us", LinkConditions{}.getNetemArgs())
+}
+
+func TestInvalidConditionsRejected(t *testing.T) {
+	conditioner := newLinkConditioner(newRecordingCommandRunner())
+	err := conditioner.setServiceConditions("node-1", testContainer1, LinkConditions{PacketLossPercent: 101})
+	assert.Error(t, err)
+	err = conditioner.setServiceConditions("node-1", testContainer1, LinkConditions{Latency: -time.Second})
+	assert.Error(t, err)
+	err = conditioner.setPairConditions("node-1", testContainer1, "node-1", testContainer1, LinkConditions{})
+	assert.Error(t, err, "A service can't have a link with itself")
+}
+
+func TestServiceConditionsUseDefaultClass(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	conditioner := newLinkConditioner(runner)
+	err := conditioner.setServiceConditions("node-1", testContainer1, LinkConditions{Latency: time.Millisecond})
+	assert.NoError(t, err)
+
+	command := getLastCommand(runner, "container-1")
+	assert.Contains(t, command, "tc qdisc add dev eth0 root handle 1: htb default 1")
+	assert.Contains(t, command, "tc qdisc add dev eth0 parent 1:1 netem delay 1000us 0us")
+	assert.NotContains(t, command, "tc filter")
+}
+
+func TestPairConditionsAppliedToBothServices(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	conditioner := newLinkConditioner(runner)
+	err := conditioner.setPairConditions("node-1", testContainer1, "node-2", testContainer2, LinkConditions{PacketLossPercent: 10})
+	assert.NoError(t, err)
+
+	command1 := getLastCommand(runner, "container-1")
+	assert.Contains(t, command1, "tc qdisc add dev eth0 parent 1:2 netem delay 0us 0us loss 10%")
+	assert.Contains(t, command1, "match ip dst 172.17.0.3/32 flowid 1:2")
+	assert.NotContains(t, command1, "parent 1:1 netem", "Traffic to other peers shouldn't be degraded")
+	command2 := getLastCommand(runner, "container-2")
+	assert.Contains(t, command2, "match ip dst 172.17.0.2/32 flowid 1:2")
+
+	// Changing a service's default conditions mid-test must keep its pair conditions
+	err = conditioner.setServiceConditions("node-1", testContainer1, LinkConditions{BandwidthLimitKbps: 500})
+	assert.NoError(t, err)
+	command1 = getLastCommand(runner, "container-1")
+	assert.Contains(t, command1, "parent 1:1 netem delay 0us 0us rate 500kbit")
+	assert.Contains(t, command1, "match ip dst 172.17.0.3/32 flowid 1:2")
+}
+
+func TestClearRemovesAllShaping(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	conditioner := newLinkConditioner(runner)
+	err := conditioner.setPairConditions("node-1", testContainer1, "node-2", testContainer2, LinkConditions{Latency: time.Second})
+	assert.NoError(t, err)
+
+	err = conditioner.clear()
+	assert.NoError(t, err)
+	for _, containerId := range []string{"container-1", "container-2"} {
+		command := getLastCommand(runner, containerId)
+		assert.Contains(t, command, "tc qdisc del dev eth0 root")
+		assert.False(t, strings.Contains(command, "tc qdisc add"), "Clearing shouldn't add any shaping")
+	}
+	assert.Equal(t, 0, len(conditioner.serviceStates))
+}
+
+func TestForgottenServiceUsesNewContainer(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	conditioner := newLinkConditioner(runner)
+	err := conditioner.setServiceConditions("node-1", testContainer1, LinkConditions{Latency: time.Second})
+	assert.NoError(t, err)
+
+	// As when the service is upgraded, and so replaced by a new container with the same service ID
+	conditioner.forgetService("node-1")
+	replacementContainer := serviceContainer{containerId: "container-1-replacement", ipAddr: "172.17.0.4"}
+	err = conditioner.setServiceConditions("node-1", replacementContainer, LinkConditions{Latency: time.Second})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(runner.commands["container-1"]), "Nothing should be run in the replaced container")
+	assert.Equal(t, 1, len(runner.commands["container-1-replacement"]))
+
+	err = conditioner.clear()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(runner.commands["container-1"]))
+}
@@ -1,17 +1,22 @@
 package ava_networks
 
 type NetworkGenesisConfig struct {
-	Stakers         []StakerIdentity
+	// The value that nodes are started with for Gecko's --network-id flag, which must name a network whose genesis Gecko has built in (e.g. "local")
+	NetworkID string
+
+	Stakers []StakerIdentity
+
+	// The funded address that tests draw funds from
 	FundedAddresses FundedAddress
 }
 
 type FundedAddress struct {
-	Address string
+	Address    string
 	PrivateKey string
 }
 
 type StakerIdentity struct {
-	NodeID string
+	NodeID     string
 	PrivateKey string
-	TlsCert string
+	TlsCert    string
 }
@@ -0,0 +1,160 @@
+package ava_networks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// The iptables chain holding a container's partition rules, which gets jumped to from the INPUT and OUTPUT chains so
+	//  that healing can remove every partition rule by flushing just this chain
+	partitionIptablesChain = "GECKO_PARTITION"
+)
+
+// The Docker identity of a service in the network
+type serviceContainer struct {
+	containerId string
+	ipAddr      string
+}
+
+/*
+Splits the network into groups of services which can't reach each other by dropping traffic between them with iptables
+rules, and heals the network by removing those rules
+*/
+type networkPartitioner struct {
+	commandRunner ContainerNetworkCommandRunner
+
+	// Guards partitionedContainers, which is shared between all copies of the network
+	mutex *sync.Mutex
+
+	// Service ID -> container of every service that currently has partition rules
+	partitionedContainers map[networks.ServiceID]serviceContainer
+}
+
+func newNetworkPartitioner(commandRunner ContainerNetworkCommandRunner) *networkPartitioner {
+	return &networkPartitioner{
+		commandRunner:         commandRunner,
+		mutex:                 &sync.Mutex{},
+		partitionedContainers: make(map[networks.ServiceID]serviceContainer),
+	}
+}
+
+/*
+Partitions the network, replacing any partition already in place
+
+Args:
+	groups: The services in each group, mapped to their containers; services in different groups won't be able to reach
+		each other
+*/
+func (partitioner *networkPartitioner) partition(groups []map[networks.ServiceID]serviceContainer) error {
+	if len(groups) < 2 {
+		return stacktrace.NewError("Partitioning requires at least two groups, but got %v", len(groups))
+	}
+	groupOfService := make(map[networks.ServiceID]int)
+	for groupIdx, group := range groups {
+		for serviceId := range group {
+			if otherGroupIdx, found := groupOfService[serviceId]; found {
+				return stacktrace.NewError("Service %v is in both group %v and group %v", serviceId, otherGroupIdx, groupIdx)
+			}
+			groupOfService[serviceId] = groupIdx
+		}
+	}
+
+	partitioner.mutex.Lock()
+	defer partitioner.mutex.Unlock()
+	if err := partitioner.healWithoutLocking(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred healing the existing partition before partitioning")
+	}
+
+	for _, serviceId := range getSortedServiceIds(groupOfService) {
+		groupIdx := groupOfService[serviceId]
+		container := groups[groupIdx][serviceId]
+		unreachableIps := []string{}
+		for otherGroupIdx, otherGroup := range groups {
+			if otherGroupIdx == groupIdx {
+				continue
+			}
+			for _, otherContainer := range otherGroup {
+				unreachableIps = append(unreachableIps, otherContainer.ipAddr)
+			}
+		}
+		sort.Strings(unreachableIps)
+
+		// The container is recorded before the rules are applied so that healing cleans up after a partial failure
+		partitioner.partitionedContainers[serviceId] = container
+		if err := partitioner.commandRunner.RunInNetworkNamespace(container.containerId, getPartitionCommand(unreachableIps)); err != nil {
+			return stacktrace.Propagate(err, "An error occurred cutting service %v off from the services in other groups", serviceId)
+		}
+	}
+	return nil
+}
+
+// Removes the partition rules from every service, so that all services can reach each other again
+func (partitioner *networkPartitioner) heal() error {
+	partitioner.mutex.Lock()
+	defer partitioner.mutex.Unlock()
+	return partitioner.healWithoutLocking()
+}
+
+func (partitioner *networkPartitioner) healWithoutLocking() error {
+	// The chain won't exist if the container was restarted since it was partitioned, in which case there's nothing to heal
+	healCommand := fmt.Sprintf(
+		"! iptables -L %v -n >/dev/null 2>&1 || iptables -F %v",
+		partitionIptablesChain,
+		partitionIptablesChain)
+	failedServiceIds := []string{}
+	for serviceId, container := range partitioner.partitionedContainers {
+		if err := partitioner.commandRunner.RunInNetworkNamespace(container.containerId, healCommand); err != nil {
+			failedServiceIds = append(failedServiceIds, string(serviceId))
+			continue
+		}
+		delete(partitioner.partitionedContainers, serviceId)
+	}
+	if len(failedServiceIds) > 0 {
+		sort.Strings(failedServiceIds)
+		return stacktrace.NewError("Could not remove partition rules from services: %v", strings.Join(failedServiceIds, ", "))
+	}
+	return nil
+}
+
+/*
+Stops tracking the partition rules of a service whose container has been removed, so that healing doesn't try to run
+commands in the removed container. The rules other services have against the removed container's IP are kept.
+*/
+func (partitioner *networkPartitioner) forgetService(serviceId networks.ServiceID) {
+	partitioner.mutex.Lock()
+	defer partitioner.mutex.Unlock()
+	delete(partitioner.partitionedContainers, serviceId)
+}
+
+// Gets a shell command which makes a container drop all traffic to and from the given IPs
+func getPartitionCommand(unreachableIps []string) string {
+	commands := []string{
+		"set -e",
+		// The chain may still exist from an earlier partition, in which case it'll have already been flushed by healing
+		fmt.Sprintf("iptables -N %v 2>/dev/null || iptables -F %v", partitionIptablesChain, partitionIptablesChain),
+		fmt.Sprintf("iptables -C INPUT -j %v 2>/dev/null || iptables -I INPUT -j %v", partitionIptablesChain, partitionIptablesChain),
+		fmt.Sprintf("iptables -C OUTPUT -j %v 2>/dev/null || iptables -I OUTPUT -j %v", partitionIptablesChain, partitionIptablesChain),
+	}
+	for _, ipAddr := range unreachableIps {
+		commands = append(commands, fmt.Sprintf("iptables -A %v -s %v -j DROP", partitionIptablesChain, ipAddr))
+		commands = append(commands, fmt.Sprintf("iptables -A %v -d %v -j DROP", partitionIptablesChain, ipAddr))
+	}
+	return strings.Join(commands, "\n")
+}
+
+func getSortedServiceIds(serviceIdSet map[networks.ServiceID]int) []networks.ServiceID {
+	result := make([]networks.ServiceID, 0, len(serviceIdSet))
+	for serviceId := range serviceIdSet {
+		result = append(result, serviceId)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
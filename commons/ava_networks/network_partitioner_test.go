@@ -0,0 +1,127 @@
+package ava_networks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/palantir/stacktrace"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingCommandRunner struct {
+	// Container ID -> commands run in the container's network namespace, in order
+	commands map[string][]string
+
+	failingContainerIds map[string]bool
+}
+
+func newRecordingCommandRunner() *recordingCommandRunner {
+	return &recordingCommandRunner{
+		commands:            make(map[string][]string),
+		failingContainerIds: make(map[string]bool),
+	}
+}
+
+func (runner *recordingCommandRunner) RunInNetworkNamespace(containerId string, shellCommand string) error {
+	runner.commands[containerId] = append(runner.commands[containerId], shellCommand)
+	if runner.failingContainerIds[containerId] {
+		return stacktrace.NewError("Test error for container %v", containerId)
+	}
+	return nil
+}
+
+func getTestGroups() []map[networks.ServiceID]serviceContainer {
+	return []map[networks.ServiceID]serviceContainer{
+		{
+			"node-a1": {containerId: "container-a1", ipAddr: "172.17.0.2"},
+			"node-a2": {containerId: "container-a2", ipAddr: "172.17.0.3"},
+		},
+		{
+			"node-b1": {containerId: "container-b1", ipAddr: "172.17.0.4"},
+		},
+	}
+}
+
+func TestPartitionDropsTrafficBetweenGroups(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	partitioner := newNetworkPartitioner(runner)
+
+	err := partitioner.partition(getTestGroups())
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, len(runner.commands["container-a1"]))
+	a1Command := runner.commands["container-a1"][0]
+	assert.Contains(t, a1Command, "-s 172.17.0.4 -j DROP")
+	assert.Contains(t, a1Command, "-d 172.17.0.4 -j DROP")
+	assert.NotContains(t, a1Command, "172.17.0.3", "Services in the same group should be able to reach each other")
+
+	b1Command := runner.commands["container-b1"][0]
+	assert.Contains(t, b1Command, "-s 172.17.0.2 -j DROP")
+	assert.Contains(t, b1Command, "-s 172.17.0.3 -j DROP")
+}
+
+func TestPartitionRejectsBadGroups(t *testing.T) {
+	partitioner := newNetworkPartitioner(newRecordingCommandRunner())
+
+	err := partitioner.partition(getTestGroups()[:1])
+	assert.Error(t, err, "A single group isn't a partition")
+
+	overlappingGroups := getTestGroups()
+	overlappingGroups[1]["node-a1"] = serviceContainer{containerId: "container-a1", ipAddr: "172.17.0.2"}
+	err = partitioner.partition(overlappingGroups)
+	assert.Error(t, err, "A service can't be in two groups")
+}
+
+func TestHealFlushesPartitionedContainers(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	partitioner := newNetworkPartitioner(runner)
+	err := partitioner.partition(getTestGroups())
+	assert.NoError(t, err)
+
+	err = partitioner.heal()
+	assert.NoError(t, err)
+	for _, containerId := range []string{"container-a1", "container-a2", "container-b1"} {
+		containerCommands := runner.commands[containerId]
+		assert.Equal(t, 2, len(containerCommands))
+		assert.True(t, strings.Contains(containerCommands[1], "iptables -F "), "Healing should flush the partition chain")
+	}
+
+	// Healing again is a no-op, since nothing is partitioned
+	err = partitioner.heal()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(runner.commands["container-a1"]))
+}
+
+func TestHealRetriesFailedContainers(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	partitioner := newNetworkPartitioner(runner)
+	err := partitioner.partition(getTestGroups())
+	assert.NoError(t, err)
+
+	runner.failingContainerIds["container-b1"] = true
+	err = partitioner.heal()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "node-b1")
+
+	runner.failingContainerIds["container-b1"] = false
+	err = partitioner.heal()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(runner.commands["container-b1"]), "Only the container that failed to heal should be healed again")
+	assert.Equal(t, 2, len(runner.commands["container-a1"]))
+}
+
+func TestHealSkipsForgottenServices(t *testing.T) {
+	runner := newRecordingCommandRunner()
+	partitioner := newNetworkPartitioner(runner)
+	err := partitioner.partition(getTestGroups())
+	assert.NoError(t, err)
+
+	// The container is gone, so running anything in it would fail
+	runner.failingContainerIds["container-b1"] = true
+	partitioner.forgetService("node-b1")
+	err = partitioner.heal()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(runner.commands["container-b1"]), "A forgotten service shouldn't be healed")
+	assert.Equal(t, 2, len(runner.commands["container-a1"]))
+}
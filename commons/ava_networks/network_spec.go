@@ -0,0 +1,441 @@
+package ava_networks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/palantir/stacktrace"
+)
+
+// Strategies for where the nodes of a service config in a network spec get their certs
+const (
+	// Every node gets a different randomly-generated cert
+	CERT_STRATEGY_VARY = "vary"
+
+	// Every node gets the same randomly-generated cert (and so the same node ID)
+	CERT_STRATEGY_SAME = "same"
+
+	// Nodes get certs from a directory of pre-generated certs, as loaded by cert_providers.LoadGeckoCertPoolFromDirectory
+	CERT_STRATEGY_POOL_DIRECTORY = "poolDirectory"
+
+	// Nodes get certs from a bundle of pre-generated certs, as loaded by cert_providers.LoadGeckoCertPoolFromBundle
+	CERT_STRATEGY_POOL_BUNDLE = "poolBundle"
+)
+
+// The extension of the spec files in a network specs directory
+const networkSpecFileExtension = ".json"
+
+/*
+A declarative description of a TestGeckoNetwork, which can be written as JSON so that tests can be parameterized over
+network topologies without Go changes. Images may reference variables (e.g. "${GECKO_IMAGE}"), which are filled in when
+the spec is turned into a loader.
+*/
+type NetworkSpec struct {
+	// Required, so that a spec can't silently start a non-staking network
+	IsStaking *bool `json:"isStaking"`
+
+	// If unset, the network starts from DefaultLocalNetGenesisConfig
+	Genesis *GenesisSpec `json:"genesis"`
+
+	BootNodes BootNodesSpec `json:"bootNodes"`
+
+	// Service config ID -> config
+	ServiceConfigs map[string]ServiceConfigSpec `json:"serviceConfigs"`
+
+	Services []ServiceSpec `json:"services"`
+}
+
+/*
+A genesis with generated stakers, built with GenesisBuilder; as a built genesis's stakers aren't the 'local' network's
+validators, it can only be used by networks without staking
+*/
+type GenesisSpec struct {
+	// The number of stakers, and so boot nodes
+	NumStakers int `json:"numStakers"`
+
+	// Generating stakers is slow, so the genesis is built the first time a loader is created from the spec and then reused
+	mutex       sync.Mutex
+	builtConfig *NetworkGenesisConfig
+}
+
+type BootNodesSpec struct {
+	Image          string `json:"image"`
+	LogLevel       string `json:"logLevel"`
+	SnowQuorumSize int    `json:"snowQuorumSize"`
+	SnowSampleSize int    `json:"snowSampleSize"`
+}
+
+type ServiceConfigSpec struct {
+	Image          string    `json:"image"`
+	LogLevel       string    `json:"logLevel"`
+	SnowQuorumSize int       `json:"snowQuorumSize"`
+	SnowSampleSize int       `json:"snowSampleSize"`
+	Certs          CertsSpec `json:"certs"`
+
+	// Additional Gecko flags, keyed by flag name (e.g. {"api-admin-enabled": true})
+	NodeConfig ava_services.GeckoNodeConfig `json:"nodeConfig"`
+
+	AdditionalBootstrappedChains []string `json:"additionalBootstrappedChains"`
+}
+
+type CertsSpec struct {
+	// One of the CERT_STRATEGY constants, defaulting to CERT_STRATEGY_VARY if empty
+	Strategy string `json:"strategy"`
+
+	// The directory or bundle file to load certs from, for the pool strategies
+	Path string `json:"path"`
+
+	// For the pool strategies, the index of the cert in the pool that every node will get; if unset, nodes get the
+	//  pool's certs round-robin
+	Index *int `json:"index"`
+}
+
+type ServiceSpec struct {
+	Id     string `json:"id"`
+	Config string `json:"config"`
+
+	// If unset, one service is created with ID Id; if set, it must be positive, and this many services are created with
+	//  IDs of the form "<id>-<index>" so that a group's IDs keep the same form whatever its size
+	Count *int `json:"count"`
+}
+
+// Network specs keyed by name
+type NetworkSpecs map[string]NetworkSpec
+
+/*
+Parses a JSON network spec, rejecting fields that the spec doesn't have so that typos don't go unnoticed
+
+Args:
+	specJson: The JSON network spec
+*/
+func ParseNetworkSpec(specJson []byte) (*NetworkSpec, error) {
+	decoder := json.NewDecoder(bytes.NewReader(specJson))
+	decoder.DisallowUnknownFields()
+	var spec NetworkSpec
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, stacktrace.Propagate(err, "Could not parse network spec JSON")
+	}
+	return &spec, nil
+}
+
+// Reads & parses a JSON network spec file
+func LoadNetworkSpecFile(specFilepath string) (*NetworkSpec, error) {
+	specJson, err := ioutil.ReadFile(specFilepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not read network spec file %v", specFilepath)
+	}
+	spec, err := ParseNetworkSpec(specJson)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not parse network spec file %v", specFilepath)
+	}
+	return spec, nil
+}
+
+/*
+Loads every spec file in the given directory, keyed by filename without the extension (e.g. the spec in
+"fullyConnectedTest.json" is named "fullyConnectedTest")
+*/
+func LoadNetworkSpecsDirectory(dirpath string) (NetworkSpecs, error) {
+	fileInfos, err := ioutil.ReadDir(dirpath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not list the network specs directory %v", dirpath)
+	}
+	result := NetworkSpecs{}
+	for _, fileInfo := range fileInfos {
+		filename := fileInfo.Name()
+		if fileInfo.IsDir() || filepath.Ext(filename) != networkSpecFileExtension {
+			continue
+		}
+		spec, err := LoadNetworkSpecFile(filepath.Join(dirpath, filename))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not load network spec file %v", filename)
+		}
+		result[strings.TrimSuffix(filename, networkSpecFileExtension)] = *spec
+	}
+	return result, nil
+}
+
+/*
+Parses a JSON object of network specs keyed by name, as serialized from a NetworkSpecs, parsing each spec as strictly as
+ParseNetworkSpec does
+
+Args:
+	specsJson: The JSON object of network specs
+*/
+func ParseNetworkSpecs(specsJson []byte) (NetworkSpecs, error) {
+	var rawSpecs map[string]json.RawMessage
+	if err := json.Unmarshal(specsJson, &rawSpecs); err != nil {
+		return nil, stacktrace.Propagate(err, "Could not parse network specs JSON")
+	}
+	result := NetworkSpecs{}
+	for name, rawSpec := range rawSpecs {
+		spec, err := ParseNetworkSpec(rawSpec)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not parse network spec %v", name)
+		}
+		result[name] = *spec
+	}
+	return result, nil
+}
+
+/*
+Validates the spec and turns it into a loader; validation errors name the offending field (e.g.
+"serviceConfigs.normal.snowQuorumSize")
+
+Args:
+	imageVariables: Values for the variables referenced in the spec's images
+*/
+func (spec NetworkSpec) CreateLoader(imageVariables map[string]string) (*TestGeckoNetworkLoader, error) {
+	if spec.IsStaking == nil {
+		return nil, newSpecFieldError("isStaking", "must be set")
+	}
+	genesisConfig, err := spec.getGenesisConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	bootNodeImage, err := expandImage("bootNodes.image", spec.BootNodes.Image, imageVariables)
+	if err != nil {
+		return nil, err
+	}
+	bootNodeLogLevel, err := validateLogLevel("bootNodes.logLevel", spec.BootNodes.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSnowParams("bootNodes", spec.BootNodes.SnowQuorumSize, spec.BootNodes.SnowSampleSize); err != nil {
+		return nil, err
+	}
+
+	if len(spec.ServiceConfigs) == 0 {
+		return nil, newSpecFieldError("serviceConfigs", "must have at least one config")
+	}
+	serviceConfigs := make(map[networks.ConfigurationID]TestGeckoNetworkServiceConfig)
+	for _, configId := range getSortedKeys(spec.ServiceConfigs) {
+		config, err := spec.ServiceConfigs[configId].toServiceConfig("serviceConfigs."+configId, imageVariables)
+		if err != nil {
+			return nil, err
+		}
+		serviceConfigs[networks.ConfigurationID(configId)] = *config
+	}
+
+	if len(spec.Services) == 0 {
+		return nil, newSpecFieldError("services", "must have at least one service")
+	}
+	desiredServices := make(map[networks.ServiceID]networks.ConfigurationID)
+	for i, service := range spec.Services {
+		fieldPath := fmt.Sprintf("services[%v]", i)
+		if service.Id == "" {
+			return nil, newSpecFieldError(fieldPath+".id", "must be set")
+		}
+		if _, found := spec.ServiceConfigs[service.Config]; !found {
+			return nil, newSpecFieldError(fieldPath+".config", "no service config with ID '%v'", service.Config)
+		}
+		if service.Count != nil && *service.Count <= 0 {
+			return nil, newSpecFieldError(fieldPath+".count", "must be positive, but was %v", *service.Count)
+		}
+		for _, serviceId := range service.getServiceIds() {
+			if _, found := desiredServices[serviceId]; found {
+				return nil, newSpecFieldError(fieldPath+".id", "service ID '%v' is used more than once", serviceId)
+			}
+			desiredServices[serviceId] = networks.ConfigurationID(service.Config)
+		}
+	}
+
+	loader, err := NewTestGeckoNetworkLoader(
+		*spec.IsStaking,
+		*genesisConfig,
+		bootNodeImage,
+		bootNodeLogLevel,
+		spec.BootNodes.SnowQuorumSize,
+		spec.BootNodes.SnowSampleSize,
+		serviceConfigs,
+		desiredServices)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not create a network loader from the network spec")
+	}
+	return loader, nil
+}
+
+func (spec NetworkSpec) getGenesisConfig() (*NetworkGenesisConfig, error) {
+	if spec.Genesis == nil {
+		return &DefaultLocalNetGenesisConfig, nil
+	}
+	if *spec.IsStaking {
+		return nil, newSpecFieldError("genesis", "can only be set for networks without staking, as Gecko v0.5.7's 'local' validators are hardcoded")
+	}
+	if spec.Genesis.NumStakers <= 0 {
+		return nil, newSpecFieldError("genesis.numStakers", "must be positive, but was %v", spec.Genesis.NumStakers)
+	}
+	return spec.Genesis.getBuiltConfig()
+}
+
+func (genesis *GenesisSpec) getBuiltConfig() (*NetworkGenesisConfig, error) {
+	genesis.mutex.Lock()
+	defer genesis.mutex.Unlock()
+	if genesis.builtConfig == nil {
+		builtConfig, err := NewGenesisBuilder(genesis.NumStakers).Build()
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not build the genesis of the network spec")
+		}
+		genesis.builtConfig = builtConfig
+	}
+	return genesis.builtConfig, nil
+}
+
+func (config ServiceConfigSpec) toServiceConfig(fieldPath string, imageVariables map[string]string) (*TestGeckoNetworkServiceConfig, error) {
+	image, err := expandImage(fieldPath+".image", config.Image, imageVariables)
+	if err != nil {
+		return nil, err
+	}
+	logLevel, err := validateLogLevel(fieldPath+".logLevel", config.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSnowParams(fieldPath, config.SnowQuorumSize, config.SnowSampleSize); err != nil {
+		return nil, err
+	}
+
+	if err := ava_services.ValidateAdditionalNodeConfig(config.NodeConfig); err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid network spec field %v.nodeConfig: not a valid set of additional Gecko flags", fieldPath)
+	}
+
+	serviceConfig := NewTestGeckoNetworkServiceConfig(
+		config.Certs.Strategy != CERT_STRATEGY_SAME,
+		logLevel,
+		image,
+		config.SnowQuorumSize,
+		config.SnowSampleSize,
+		config.NodeConfig,
+		config.AdditionalBootstrappedChains)
+
+	certsFieldPath := fieldPath + ".certs"
+	switch config.Certs.Strategy {
+	case "", CERT_STRATEGY_VARY, CERT_STRATEGY_SAME:
+		if config.Certs.Path != "" || config.Certs.Index != nil {
+			return nil, newSpecFieldError(certsFieldPath, "path and index are only used by the pool strategies")
+		}
+		return serviceConfig, nil
+	case CERT_STRATEGY_POOL_DIRECTORY, CERT_STRATEGY_POOL_BUNDLE:
+		certProvider, err := config.Certs.createPoolProvider(certsFieldPath)
+		if err != nil {
+			return nil, err
+		}
+		configWithProvider := serviceConfig.WithCertProvider(certProvider)
+		return &configWithProvider, nil
+	default:
+		return nil, newSpecFieldError(
+			certsFieldPath+".strategy",
+			"unknown strategy '%v'; must be one of %v, %v, %v, %v",
+			config.Certs.Strategy,
+			CERT_STRATEGY_VARY,
+			CERT_STRATEGY_SAME,
+			CERT_STRATEGY_POOL_DIRECTORY,
+			CERT_STRATEGY_POOL_BUNDLE)
+	}
+}
+
+func (certs CertsSpec) createPoolProvider(fieldPath string) (cert_providers.GeckoCertProvider, error) {
+	if certs.Path == "" {
+		return nil, newSpecFieldError(fieldPath+".path", "must be set for strategy '%v'", certs.Strategy)
+	}
+	var pool *cert_providers.GeckoCertPool
+	var err error
+	if certs.Strategy == CERT_STRATEGY_POOL_DIRECTORY {
+		pool, err = cert_providers.LoadGeckoCertPoolFromDirectory(certs.Path)
+	} else {
+		pool, err = cert_providers.LoadGeckoCertPoolFromBundle(certs.Path)
+	}
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid network spec field %v.path: could not load cert pool from '%v'", fieldPath, certs.Path)
+	}
+
+	if certs.Index == nil {
+		return pool.GetRoundRobinProvider(), nil
+	}
+	provider, err := pool.GetIndexedProvider(*certs.Index)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Invalid network spec field %v.index: no cert at index %v", fieldPath, *certs.Index)
+	}
+	return provider, nil
+}
+
+func (service ServiceSpec) getServiceIds() []networks.ServiceID {
+	if service.Count == nil {
+		return []networks.ServiceID{networks.ServiceID(service.Id)}
+	}
+	result := make([]networks.ServiceID, 0, *service.Count)
+	for i := 0; i < *service.Count; i++ {
+		result = append(result, networks.ServiceID(service.Id+"-"+strconv.Itoa(i)))
+	}
+	return result
+}
+
+func expandImage(fieldPath string, image string, imageVariables map[string]string) (string, error) {
+	undefinedVariables := []string{}
+	expanded := os.Expand(image, func(variable string) string {
+		value, found := imageVariables[variable]
+		if !found {
+			undefinedVariables = append(undefinedVariables, variable)
+		}
+		return value
+	})
+	if len(undefinedVariables) > 0 {
+		return "", newSpecFieldError(fieldPath, "references undefined variables %v", undefinedVariables)
+	}
+	if expanded == "" {
+		return "", newSpecFieldError(fieldPath, "must be set")
+	}
+	return expanded, nil
+}
+
+func validateLogLevel(fieldPath string, logLevelStr string) (ava_services.GeckoLogLevel, error) {
+	logLevel := ava_services.GeckoLogLevel(logLevelStr)
+	if !ava_services.ValidLogLevels[logLevel] {
+		return "", newSpecFieldError(
+			fieldPath,
+			"invalid log level '%v'; must be one of %v, %v, %v",
+			logLevelStr,
+			ava_services.LOG_LEVEL_VERBOSE,
+			ava_services.LOG_LEVEL_DEBUG,
+			ava_services.LOG_LEVEL_INFO)
+	}
+	return logLevel, nil
+}
+
+func validateSnowParams(fieldPathPrefix string, snowQuorumSize int, snowSampleSize int) error {
+	if snowSampleSize <= 0 {
+		return newSpecFieldError(fieldPathPrefix+".snowSampleSize", "must be positive, but was %v", snowSampleSize)
+	}
+	if snowQuorumSize <= 0 || snowQuorumSize > snowSampleSize {
+		return newSpecFieldError(
+			fieldPathPrefix+".snowQuorumSize",
+			"must be positive and no greater than the sample size (%v), but was %v",
+			snowSampleSize,
+			snowQuorumSize)
+	}
+	return nil
+}
+
+func newSpecFieldError(fieldPath string, format string, args ...interface{}) error {
+	return stacktrace.NewError("Invalid network spec field %v: %v", fieldPath, fmt.Sprintf(format, args...))
+}
+
+func getSortedKeys(serviceConfigs map[string]ServiceConfigSpec) []string {
+	result := make([]string, 0, len(serviceConfigs))
+	for key := range serviceConfigs {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,220 @@
+package ava_networks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/stretchr/testify/assert"
+)
+
+const validSpecJson = `{
+	"isStaking": true,
+	"bootNodes": {
+		"image": "${GECKO_IMAGE}",
+		"logLevel": "debug",
+		"snowQuorumSize": 2,
+		"snowSampleSize": 2
+	},
+	"serviceConfigs": {
+		"normal": {
+			"image": "${GECKO_IMAGE}",
+			"logLevel": "info",
+			"snowQuorumSize": 2,
+			"snowSampleSize": 3,
+			"nodeConfig": {"api-admin-enabled": true}
+		},
+		"duplicate": {
+			"image": "gecko-byzantine:latest",
+			"logLevel": "verbo",
+			"snowQuorumSize": 1,
+			"snowSampleSize": 1,
+			"certs": {"strategy": "same"}
+		}
+	},
+	"services": [
+		{"id": "validator", "config": "normal"},
+		{"id": "normal-node", "config": "normal", "count": 2},
+		{"id": "duplicate-node", "config": "duplicate", "count": 2}
+	]
+}`
+
+var testImageVariables = map[string]string{
+	"GECKO_IMAGE": "gecko:latest",
+}
+
+func createLoaderFromSpecJson(specJson string) (*TestGeckoNetworkLoader, error) {
+	spec, err := ParseNetworkSpec([]byte(specJson))
+	if err != nil {
+		return nil, err
+	}
+	return spec.CreateLoader(testImageVariables)
+}
+
+func TestValidSpecCreatesLoader(t *testing.T) {
+	loader, err := createLoaderFromSpecJson(validSpecJson)
+	assert.NoError(t, err)
+
+	assert.True(t, loader.isStaking)
+	assert.Equal(t, "gecko:latest", loader.bootNodeImage)
+	assert.Equal(t, ava_services.LOG_LEVEL_DEBUG, loader.bootNodeLogLevel)
+
+	normalConfig := loader.serviceConfigs["normal"]
+	assert.Equal(t, "gecko:latest", normalConfig.imageName)
+	assert.Equal(t, ava_services.LOG_LEVEL_INFO, normalConfig.serviceLogLevel)
+	assert.Equal(t, 2, normalConfig.snowQuorumSize)
+	assert.Equal(t, 3, normalConfig.snowSampleSize)
+	assert.True(t, normalConfig.varyCerts)
+	assert.Equal(t, ava_services.BoolFlag(true), normalConfig.nodeConfig.ApiAdminEnabled)
+	assert.False(t, loader.serviceConfigs["duplicate"].varyCerts)
+
+	expectedServices := map[networks.ServiceID]networks.ConfigurationID{
+		"validator":        "normal",
+		"normal-node-0":    "normal",
+		"normal-node-1":    "normal",
+		"duplicate-node-0": "duplicate",
+		"duplicate-node-1": "duplicate",
+	}
+	assert.Equal(t, expectedServices, loader.desiredServiceConfig)
+	assert.Equal(t, DefaultLocalNetGenesisConfig, loader.genesisConfig)
+}
+
+func TestSpecGenesisIsBuiltOnce(t *testing.T) {
+	spec, err := ParseNetworkSpec([]byte(`{
+		"isStaking": false,
+		"genesis": {"numStakers": 1},
+		"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 1, "snowSampleSize": 1},
+		"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 1, "snowSampleSize": 1}},
+		"services": [{"id": "node", "config": "normal"}]
+	}`))
+	assert.NoError(t, err)
+
+	firstLoader, err := spec.CreateLoader(testImageVariables)
+	assert.NoError(t, err)
+	secondLoader, err := spec.CreateLoader(testImageVariables)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(firstLoader.genesisConfig.Stakers))
+	assert.Equal(t, firstLoader.genesisConfig, secondLoader.genesisConfig)
+}
+
+func TestSpecsSurviveSerialization(t *testing.T) {
+	spec, err := ParseNetworkSpec([]byte(validSpecJson))
+	assert.NoError(t, err)
+	specsJson, err := json.Marshal(NetworkSpecs{"valid": *spec})
+	assert.NoError(t, err)
+
+	parsedSpecs, err := ParseNetworkSpecs(specsJson)
+	assert.NoError(t, err)
+	assert.Equal(t, NetworkSpecs{"valid": *spec}, parsedSpecs)
+}
+
+func TestLoadSpecsDirectory(t *testing.T) {
+	dirpath, err := ioutil.TempDir("", "network-specs")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirpath)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dirpath, "validTopology.json"), []byte(validSpecJson), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dirpath, "README.md"), []byte("Not a spec"), 0644))
+
+	specs, err := LoadNetworkSpecsDirectory(dirpath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(specs))
+	_, found := specs["validTopology"]
+	assert.True(t, found)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dirpath, "invalidTopology.json"), []byte(`{"bootNode": {}}`), 0644))
+	_, err = LoadNetworkSpecsDirectory(dirpath)
+	assert.Error(t, err, "A directory with an invalid spec file should be rejected")
+}
+
+func TestSpecRejectsUnknownFields(t *testing.T) {
+	_, err := ParseNetworkSpec([]byte(`{"isStaking": true, "bootNode": {}}`))
+	assert.Error(t, err)
+}
+
+func TestSpecErrorsNameOffendingField(t *testing.T) {
+	invalidSpecs := map[string]string{
+		"isStaking": `{
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"bootNodes.image": `{
+			"isStaking": true,
+			"bootNodes": {"image": "${UNDEFINED}", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"serviceConfigs.normal.logLevel": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "loud", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"serviceConfigs.normal.snowQuorumSize": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 3, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"serviceConfigs.normal.certs.strategy": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2, "certs": {"strategy": "magic"}}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"serviceConfigs.normal.certs.path": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2, "certs": {"strategy": "poolDirectory"}}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"serviceConfigs.normal.nodeConfig": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2, "nodeConfig": {"snow-sample-size": 4}}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"services[1].config": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}, {"id": "other-node", "config": "abnormal"}]
+		}`,
+		"services[0].count": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal", "count": 0}]
+		}`,
+		"genesis": `{
+			"isStaking": true,
+			"genesis": {"numStakers": 3},
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"genesis.numStakers": `{
+			"isStaking": false,
+			"genesis": {"numStakers": 0},
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}]
+		}`,
+		"services[1].id": `{
+			"isStaking": true,
+			"bootNodes": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2},
+			"serviceConfigs": {"normal": {"image": "gecko", "logLevel": "debug", "snowQuorumSize": 2, "snowSampleSize": 2}},
+			"services": [{"id": "node", "config": "normal"}, {"id": "node", "config": "normal"}]
+		}`,
+	}
+	for expectedField, specJson := range invalidSpecs {
+		_, err := createLoaderFromSpecJson(specJson)
+		if assert.Error(t, err, "Spec with invalid %v should be rejected", expectedField) {
+			assert.Contains(t, err.Error(), "field "+expectedField+":")
+		}
+	}
+}
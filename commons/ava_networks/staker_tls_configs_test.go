@@ -0,0 +1,18 @@
+package ava_networks
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
+	"github.com/stretchr/testify/assert"
+)
+
+// The default stakers' node IDs are hardcoded in Gecko, so this verifies that node IDs are computed exactly as Gecko does
+func TestDefaultStakerNodeIdsMatchCerts(t *testing.T) {
+	for i, staker := range DefaultLocalNetGenesisConfig.Stakers {
+		nodeId, err := cert_providers.ComputeNodeId(*bytes.NewBufferString(staker.TlsCert))
+		assert.NoError(t, err)
+		assert.Equal(t, staker.NodeID, nodeId, "Computed node ID of default staker %v doesn't match Gecko's", i)
+	}
+}
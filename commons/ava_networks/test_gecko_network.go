@@ -2,14 +2,20 @@ package ava_networks
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
 	"time"
 
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
 	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
 
 	"github.com/kurtosis-tech/kurtosis/commons/networks"
 	"github.com/kurtosis-tech/kurtosis/commons/services"
@@ -27,12 +33,70 @@ const (
 // ============== Network ======================
 const (
 	containerStopTimeout = 30 * time.Second
+
+	// Gecko nodes will sometimes refuse connections or return 5xx errors right after start, so clients handed out by the
+	// network retry their read-only requests
+	clientRetryMaxAttempts           = 5
+	clientRetryInitialInterval       = 500 * time.Millisecond
+	clientRetryBackoffMultiplier     = 2
+	clientRetryMaxInterval           = 5 * time.Second
+	clientRetryBackoffJitterFraction = 0.2
+
+	restartedServiceAvailabilityPollInterval = 2 * time.Second
 )
 
 type TestGeckoNetwork struct {
 	networks.Network
 
 	svcNetwork *networks.ServiceNetwork
+
+	// The genesis that the network's boot nodes were started from
+	genesisConfig NetworkGenesisConfig
+
+	// The context that all Gecko clients handed out by this network will be bound to
+	ctx context.Context
+
+	// If non-nil, all Gecko clients handed out by this network will record their requests with this recorder
+	rpcRecorder *gecko_client.RpcRecorder
+
+	// Shared between all copies of the network, so that a partition made through one copy can be healed through another
+	partitioner *networkPartitioner
+
+	// Shared between all copies of the network, for the same reason as the partitioner
+	linkConditioner *linkConditioner
+
+	containerLifecycleManager ContainerLifecycleManager
+
+	// Shared with the initializer cores of the network's services, so that upgraded services can keep their identities
+	identityRegistry *ava_services.GeckoNodeIdentityRegistry
+
+	// The configurations that user-configured services can be started with, by configuration ID
+	serviceConfigs map[networks.ConfigurationID]TestGeckoNetworkServiceConfig
+
+	// Shared between all copies of the network, so that a service added through one copy can be restarted through another
+	serviceConfigIds *serviceConfigTracker
+}
+
+/*
+Returns a copy of this network whose Gecko clients will all be bound to the given context, so that any requests they
+have in flight are aborted when the context is done
+*/
+func (network TestGeckoNetwork) WithContext(ctx context.Context) TestGeckoNetwork {
+	network.ctx = ctx
+	return network
+}
+
+// Gets the context that the network's Gecko clients are bound to, which waits in tests should be bounded by as well
+func (network TestGeckoNetwork) GetContext() context.Context {
+	return network.ctx
+}
+
+/*
+Returns a copy of this network whose Gecko clients will all record their requests with the given recorder
+*/
+func (network TestGeckoNetwork) WithRpcRecorder(recorder *gecko_client.RpcRecorder) TestGeckoNetwork {
+	network.rpcRecorder = recorder
+	return network
 }
 
 func (network TestGeckoNetwork) GetGeckoClient(serviceId networks.ServiceID) (*gecko_client.GeckoClient, error) {
@@ -42,43 +106,347 @@ func (network TestGeckoNetwork) GetGeckoClient(serviceId networks.ServiceID) (*g
 	}
 	geckoService := node.Service.(ava_services.GeckoService)
 	jsonRpcSocket := geckoService.GetJsonRpcSocket()
-	return gecko_client.NewGeckoClient(jsonRpcSocket.GetIpAddr(), jsonRpcSocket.GetPort()), nil
+	retryPolicy := gecko_client.NewRetryPolicy(
+		clientRetryMaxAttempts,
+		poller.NewJitteredBackoff(
+			poller.NewExponentialBackoff(clientRetryInitialInterval, clientRetryBackoffMultiplier, clientRetryMaxInterval),
+			clientRetryBackoffJitterFraction),
+		gecko_client.IsTransientRpcError,
+		make([]string, 0))
+	client := gecko_client.NewGeckoClient(jsonRpcSocket.GetIpAddr(), jsonRpcSocket.GetPort())
+	client = client.WithRetryPolicy(retryPolicy)
+	if network.rpcRecorder != nil {
+		// The recorder goes outside the retries so that replaying a recording reproduces what the test saw
+		client = client.WithRecorder(network.rpcRecorder, string(serviceId))
+	}
+	return client.WithContext(network.ctx), nil
+}
+
+// Gets a scraper for the Prometheus metrics of the service with the given ID, bound to this network's context
+func (network TestGeckoNetwork) GetMetricsScraper(serviceId networks.ServiceID) (*gecko_client.MetricsScraper, error) {
+	node, err := network.svcNetwork.GetService(serviceId)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred retrieving service node with ID %v", serviceId)
+	}
+	geckoService := node.Service.(ava_services.GeckoService)
+	jsonRpcSocket := geckoService.GetJsonRpcSocket()
+	scraper := gecko_client.NewMetricsScraper(jsonRpcSocket.GetIpAddr(), jsonRpcSocket.GetPort())
+	return scraper.WithContext(network.ctx), nil
+}
+
+// Gets the genesis that the network was started from, which has the identities of the boot nodes and the funded addresses
+func (network TestGeckoNetwork) GetGenesisConfig() NetworkGenesisConfig {
+	return network.genesisConfig
 }
 
 func (network TestGeckoNetwork) GetAllBootServiceIds() map[networks.ServiceID]bool {
 	result := make(map[networks.ServiceID]bool)
-	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
+	for i := 0; i < len(network.genesisConfig.Stakers); i++ {
 		bootId := networks.ServiceID(bootNodeServiceIdPrefix + strconv.Itoa(i))
 		result[bootId] = true
 	}
 	return result
 }
 
+// Gets the IDs of the services in the network that were started with the given configuration, in sorted order
+func (network TestGeckoNetwork) GetServiceIdsWithConfig(configurationId networks.ConfigurationID) []networks.ServiceID {
+	return network.serviceConfigIds.getServiceIds(configurationId)
+}
+
 func (network TestGeckoNetwork) AddService(configurationId networks.ConfigurationID, serviceId networks.ServiceID) (*services.ServiceAvailabilityChecker, error) {
-	availabilityChecker, err := network.svcNetwork.AddService(configurationId, serviceId, network.GetAllBootServiceIds())
+	var availabilityChecker *services.ServiceAvailabilityChecker
+	err := network.identityRegistry.StartService(string(serviceId), nil, func() error {
+		var err error
+		availabilityChecker, err = network.svcNetwork.AddService(configurationId, serviceId, network.GetAllBootServiceIds())
+		return err
+	})
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "An error occurred adding service with service ID %v, configuration ID %v", serviceId, configurationId)
 	}
+	network.serviceConfigIds.set(serviceId, configurationId)
 	return availabilityChecker, nil
 }
 
+/*
+Removes the given service from the network, along with any partition rules or link conditions applied to its container
+*/
 func (network TestGeckoNetwork) RemoveService(serviceId networks.ServiceID) error {
 	if err := network.svcNetwork.RemoveService(serviceId, containerStopTimeout); err != nil {
 		return stacktrace.Propagate(err, "An error occurred removing service with ID %v", serviceId)
 	}
+	network.forgetServiceContainer(serviceId)
+	network.serviceConfigIds.forget(serviceId)
 	return nil
 }
 
+/*
+Stops the container of the given service without removing it, so that the node's cert and database are kept for when
+it's started again with StartService.
+
+NOTE: Partitions and link conditions don't survive the service being stopped, and must be reapplied after it's started.
+*/
+func (network TestGeckoNetwork) StopService(serviceId networks.ServiceID) error {
+	container, err := network.getServiceContainer(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId)
+	}
+	if err := network.containerLifecycleManager.StopContainer(container.containerId, containerStopTimeout); err != nil {
+		return stacktrace.Propagate(err, "An error occurred stopping service %v", serviceId)
+	}
+	return nil
+}
+
+/*
+Kills the container of the given service without giving the node a chance to shut down, as if it crashed, keeping the
+container so that the node can be started again with StartService.
+
+NOTE: As with StopService, partitions and link conditions must be reapplied after the service is started.
+*/
+func (network TestGeckoNetwork) KillService(serviceId networks.ServiceID) error {
+	container, err := network.getServiceContainer(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId)
+	}
+	if err := network.containerLifecycleManager.KillContainer(container.containerId); err != nil {
+		return stacktrace.Propagate(err, "An error occurred killing service %v", serviceId)
+	}
+	return nil
+}
+
+/*
+Starts a service stopped with StopService or KillService, with the same IP, cert (and so node ID), and database it had
+before, and waits for it to become available again
+*/
+func (network TestGeckoNetwork) StartService(serviceId networks.ServiceID) error {
+	node, err := network.svcNetwork.GetService(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred retrieving service node with ID %v", serviceId)
+	}
+	if err := network.containerLifecycleManager.StartContainer(node.ContainerId); err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting service %v", serviceId)
+	}
+
+	availabilityCheckerCore := ava_services.NewGeckoServiceAvailabilityCheckerCore(network.getAdditionalBootstrappedChains(serviceId))
+	ctx, cancel := context.WithTimeout(network.ctx, availabilityCheckerCore.GetTimeout())
+	defer cancel()
+	availabilityPoller := poller.NewPoller(
+		fmt.Sprintf("availability of restarted service %v", serviceId),
+		poller.NewConstantBackoff(restartedServiceAvailabilityPollInterval))
+	_, err = availabilityPoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			return availabilityCheckerCore.IsServiceUp(node.Service, make([]services.Service, 0)), nil
+		},
+		poller.Equals(true))
+	if err != nil {
+		return stacktrace.Propagate(err, "Service %v didn't become available after being started", serviceId)
+	}
+	return nil
+}
+
+// Freezes all processes in the service's container, as if the node hung, until UnpauseService is called
+func (network TestGeckoNetwork) PauseService(serviceId networks.ServiceID) error {
+	container, err := network.getServiceContainer(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId)
+	}
+	if err := network.containerLifecycleManager.PauseContainer(container.containerId); err != nil {
+		return stacktrace.Propagate(err, "An error occurred pausing service %v", serviceId)
+	}
+	return nil
+}
+
+// Resumes a service paused with PauseService
+func (network TestGeckoNetwork) UnpauseService(serviceId networks.ServiceID) error {
+	container, err := network.getServiceContainer(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId)
+	}
+	if err := network.containerLifecycleManager.UnpauseContainer(container.containerId); err != nil {
+		return stacktrace.Propagate(err, "An error occurred unpausing service %v", serviceId)
+	}
+	return nil
+}
+
+/*
+Replaces the given service with a new one started from the given configuration (e.g. one with a newer Gecko image),
+which keeps the replaced node's cert (and so its node ID) and database, and waits for it to become available. The new
+service has the same service ID as the one it replaces, but a different IP.
+
+NOTE: Partitions and link conditions applied to the replaced service don't carry over to the new one, and must be
+reapplied after the upgrade.
+
+Args:
+	serviceId: The service to replace, which may be a boot node
+	newConfigId: The configuration to start the replacement service with
+*/
+func (network TestGeckoNetwork) UpgradeService(serviceId networks.ServiceID, newConfigId networks.ConfigurationID) error {
+	node, err := network.svcNetwork.GetService(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred retrieving service node with ID %v", serviceId)
+	}
+	identity, found := network.identityRegistry.GetStartedIdentity(node.IpAddr)
+	if !found {
+		return stacktrace.NewError("No identity was recorded for service %v with IP %v; this is likely a code bug", serviceId, node.IpAddr)
+	}
+
+	if err := network.svcNetwork.RemoveService(serviceId, containerStopTimeout); err != nil {
+		return stacktrace.Propagate(err, "An error occurred removing service %v to upgrade it", serviceId)
+	}
+	network.forgetServiceContainer(serviceId)
+
+	// A boot node being upgraded can't depend on itself
+	dependencies := network.GetAllBootServiceIds()
+	delete(dependencies, serviceId)
+	var availabilityChecker *services.ServiceAvailabilityChecker
+	err = network.identityRegistry.StartService(string(serviceId), &identity, func() error {
+		var err error
+		availabilityChecker, err = network.svcNetwork.AddService(newConfigId, serviceId, dependencies)
+		return err
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred adding upgraded service %v with configuration %v", serviceId, newConfigId)
+	}
+	network.serviceConfigIds.set(serviceId, newConfigId)
+	if err := availabilityChecker.WaitForStartup(); err != nil {
+		return stacktrace.Propagate(err, "Upgraded service %v didn't become available", serviceId)
+	}
+	return nil
+}
+
+/*
+Splits the network into groups of services which can't reach each other, replacing any partition already in place.
+Services that aren't in any group (e.g. boot nodes, if none are specified) can still reach every other service.
+
+Args:
+	groups: Sets of service IDs; services in different sets won't be able to reach each other
+*/
+func (network TestGeckoNetwork) Partition(groups []map[networks.ServiceID]bool) error {
+	groupContainers := []map[networks.ServiceID]serviceContainer{}
+	for _, group := range groups {
+		containers := make(map[networks.ServiceID]serviceContainer)
+		for serviceId := range group {
+			container, err := network.getServiceContainer(serviceId)
+			if err != nil {
+				return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId)
+			}
+			containers[serviceId] = *container
+		}
+		groupContainers = append(groupContainers, containers)
+	}
+	if err := network.partitioner.partition(groupContainers); err != nil {
+		return stacktrace.Propagate(err, "An error occurred partitioning the network")
+	}
+	return nil
+}
+
+// Removes any partition made with Partition, so that all services can reach each other again
+func (network TestGeckoNetwork) HealPartition() error {
+	if err := network.partitioner.heal(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred healing the network partition")
+	}
+	return nil
+}
+
+/*
+Degrades all traffic that the given service sends, except to services that it has pair conditions with (which take
+precedence). Calling this again replaces the service's conditions.
+
+Args:
+	serviceId: The service whose outgoing traffic will be degraded
+	conditions: The conditions to degrade the traffic with
+*/
+func (network TestGeckoNetwork) SetServiceLinkConditions(serviceId networks.ServiceID, conditions LinkConditions) error {
+	container, err := network.getServiceContainer(serviceId)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId)
+	}
+	if err := network.linkConditioner.setServiceConditions(serviceId, *container, conditions); err != nil {
+		return stacktrace.Propagate(err, "An error occurred setting link conditions of service %v", serviceId)
+	}
+	return nil
+}
+
+/*
+Degrades the traffic between two services, in both directions. Calling this again for the same pair replaces the pair's
+conditions.
+
+Args:
+	serviceId1: One end of the link
+	serviceId2: The other end of the link
+	conditions: The conditions to degrade the link with
+*/
+func (network TestGeckoNetwork) SetPairLinkConditions(serviceId1 networks.ServiceID, serviceId2 networks.ServiceID, conditions LinkConditions) error {
+	container1, err := network.getServiceContainer(serviceId1)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId1)
+	}
+	container2, err := network.getServiceContainer(serviceId2)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the container of service %v", serviceId2)
+	}
+	if err := network.linkConditioner.setPairConditions(serviceId1, *container1, serviceId2, *container2, conditions); err != nil {
+		return stacktrace.Propagate(err, "An error occurred setting link conditions between services %v and %v", serviceId1, serviceId2)
+	}
+	return nil
+}
+
+// Removes all link conditions set with SetServiceLinkConditions and SetPairLinkConditions
+func (network TestGeckoNetwork) ClearLinkConditions() error {
+	if err := network.linkConditioner.clear(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred clearing link conditions")
+	}
+	return nil
+}
+
+func (network TestGeckoNetwork) getServiceContainer(serviceId networks.ServiceID) (*serviceContainer, error) {
+	node, err := network.svcNetwork.GetService(serviceId)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred retrieving service node with ID %v", serviceId)
+	}
+	return &serviceContainer{
+		containerId: node.ContainerId,
+		ipAddr:      node.IpAddr,
+	}, nil
+}
+
+/*
+Gets the chains beyond the P- and X-Chains that the given service must have bootstrapped to be up, which are those of the
+configuration it was started with; boot nodes, and services the network didn't start, only need the default chains
+*/
+func (network TestGeckoNetwork) getAdditionalBootstrappedChains(serviceId networks.ServiceID) []string {
+	configId, found := network.serviceConfigIds.get(serviceId)
+	if !found {
+		return make([]string, 0)
+	}
+	config, found := network.serviceConfigs[configId]
+	if !found {
+		return make([]string, 0)
+	}
+	return config.additionalBootstrappedChains
+}
+
+// Drops the partition and link condition state of a service whose container has been removed
+func (network TestGeckoNetwork) forgetServiceContainer(serviceId networks.ServiceID) {
+	network.partitioner.forgetService(serviceId)
+	network.linkConditioner.forgetService(serviceId)
+}
+
 // ============= Loader Service Config ====================================
 type TestGeckoNetworkServiceConfig struct {
 	// Whether the certs used by services with this configuration will be different or not
 	varyCerts       bool
 	serviceLogLevel ava_services.GeckoLogLevel
 	// Used primarily for Byzantine tests but can also test heterogenous Gecko versions, for example.
-	imageName         string
-	snowQuorumSize    int
-	snowSampleSize    int
-	additionalCLIArgs map[string]string // CLI Args to pass directly to Gecko
+	imageName      string
+	snowQuorumSize int
+	snowSampleSize int
+	// Gecko flags to set beyond the ones the network sets itself (e.g. the byzantine behavior)
+	nodeConfig ava_services.GeckoNodeConfig
+	// Chains beyond the P- and X-Chains that services with this configuration must bootstrap before being considered up
+	additionalBootstrappedChains []string
+	// If non-nil, services with this configuration get their certs from this provider rather than a generated one
+	certProvider cert_providers.GeckoCertProvider
 }
 
 func NewTestGeckoNetworkServiceConfig(
@@ -87,27 +455,58 @@ func NewTestGeckoNetworkServiceConfig(
 	imageName string,
 	snowQuorumSize int,
 	snowSampleSize int,
-	additionalCLIArgs map[string]string) *TestGeckoNetworkServiceConfig {
+	nodeConfig ava_services.GeckoNodeConfig,
+	additionalBootstrappedChains []string) *TestGeckoNetworkServiceConfig {
+	// Defensive copy
+	additionalBootstrappedChainsCopy := make([]string, 0, len(additionalBootstrappedChains))
+	for _, chain := range additionalBootstrappedChains {
+		additionalBootstrappedChainsCopy = append(additionalBootstrappedChainsCopy, chain)
+	}
+
 	return &TestGeckoNetworkServiceConfig{
-		varyCerts:         varyCerts,
-		serviceLogLevel:   serviceLogLevel,
-		imageName:         imageName,
-		snowQuorumSize:    snowQuorumSize,
-		snowSampleSize:    snowSampleSize,
-		additionalCLIArgs: additionalCLIArgs,
+		varyCerts:                    varyCerts,
+		serviceLogLevel:              serviceLogLevel,
+		imageName:                    imageName,
+		snowQuorumSize:               snowQuorumSize,
+		snowSampleSize:               snowSampleSize,
+		nodeConfig:                   nodeConfig,
+		additionalBootstrappedChains: additionalBootstrappedChainsCopy,
 	}
 }
 
+/*
+Returns a copy of the config whose services get their certs from the given provider (e.g. one from a GeckoCertPool of
+pre-generated staker identities), rather than from generated certs. The config's varyCerts setting and any loader cert
+seed are ignored when a provider is set.
+
+Args:
+	certProvider: The provider that services with this configuration will get their certs from
+*/
+func (config TestGeckoNetworkServiceConfig) WithCertProvider(certProvider cert_providers.GeckoCertProvider) TestGeckoNetworkServiceConfig {
+	config.certProvider = certProvider
+	return config
+}
+
 // ============== Loader ======================
 
 type TestGeckoNetworkLoader struct {
 	bootNodeImage              string
 	bootNodeLogLevel           ava_services.GeckoLogLevel
 	isStaking                  bool
+	genesisConfig              NetworkGenesisConfig
 	serviceConfigs             map[networks.ConfigurationID]TestGeckoNetworkServiceConfig
 	desiredServiceConfig       map[networks.ServiceID]networks.ConfigurationID
 	bootstrapperSnowQuorumSize int
 	bootstrapperSnowSampleSize int
+
+	// Records the identity of every node in the network, so that nodes can be upgraded without losing them
+	identityRegistry *ava_services.GeckoNodeIdentityRegistry
+
+	// If non-nil, the certs of user-configured services will be generated from this seed rather than randomly
+	certSeed *int64
+
+	// Records the configuration every service was started with, and is handed on to the network
+	serviceConfigIds *serviceConfigTracker
 }
 
 /*
@@ -119,6 +518,8 @@ upon initialization.
 
 Args:
 	isStaking: Whether the network will have staking enabled
+	genesisConfig: The genesis the network will start from, whose stakers will be the network's boot nodes (e.g.
+		DefaultLocalNetGenesisConfig, or for a network without staking, a genesis built with GenesisBuilder)
 	bootNodeImage: The Docker image that should be used to launch the boot nodes
 	bootNodeLogLevel: The log level that the boot nodes will launch with
 	bootstrapperSnowQuorumSize: The Snow consensus sample size used for nodes in the network
@@ -128,6 +529,7 @@ Args:
 */
 func NewTestGeckoNetworkLoader(
 	isStaking bool,
+	genesisConfig NetworkGenesisConfig,
 	bootNodeImage string,
 	bootNodeLogLevel ava_services.GeckoLogLevel,
 	bootstrapperSnowQuorumSize int,
@@ -147,6 +549,9 @@ func NewTestGeckoNetworkLoader(
 											bootNodeConfigIdPrefix,
 											bootNodeConfigIdPrefix)
 		}
+		if err := ava_services.ValidateAdditionalNodeConfig(configParams.nodeConfig); err != nil {
+			return nil, stacktrace.Propagate(err, "The node config of config ID %v is invalid", configId)
+		}
 		serviceConfigsCopy[configId] = configParams
 	}
 
@@ -166,40 +571,66 @@ func NewTestGeckoNetworkLoader(
 		bootNodeImage:              bootNodeImage,
 		bootNodeLogLevel:           bootNodeLogLevel,
 		isStaking:                  isStaking,
+		genesisConfig:              genesisConfig,
 		serviceConfigs:             serviceConfigsCopy,
 		desiredServiceConfig:       desiredServiceConfigsCopy,
 		bootstrapperSnowQuorumSize: bootstrapperSnowQuorumSize,
 		bootstrapperSnowSampleSize: bootstrapperSnowSampleSize,
+		identityRegistry:           ava_services.NewGeckoNodeIdentityRegistry(),
+		serviceConfigIds:           newServiceConfigTracker(),
 	}, nil
 }
 
+/*
+Returns a copy of the loader whose user-configured services get certs generated deterministically from the given seed,
+so that a run's node IDs can be reproduced by rerunning with the same seed
+
+Args:
+	seed: The seed to generate certs from
+*/
+func (loader TestGeckoNetworkLoader) WithCertSeed(seed int64) TestGeckoNetworkLoader {
+	loader.certSeed = &seed
+	return loader
+}
+
 func (loader TestGeckoNetworkLoader) ConfigureNetwork(builder *networks.ServiceNetworkBuilder) error {
-	localNetGenesisStakers := DefaultLocalNetGenesisConfig.Stakers
-	bootNodeIds := make([]string, 0, len(localNetGenesisStakers))
-	for _, staker := range DefaultLocalNetGenesisConfig.Stakers {
-		bootNodeIds = append(bootNodeIds, staker.NodeID)
+	genesisStakers := loader.genesisConfig.Stakers
+	bootNodeCertProviders := make([]cert_providers.GeckoCertProvider, 0, len(genesisStakers))
+	bootNodeIds := make([]string, 0, len(genesisStakers))
+	for i, staker := range genesisStakers {
+		certProvider := cert_providers.NewStaticGeckoCertProvider(
+			*bytes.NewBufferString(staker.PrivateKey),
+			*bytes.NewBufferString(staker.TlsCert))
+		// The node ID is computed from the cert, rather than trusted, so that a staker whose node ID doesn't match its
+		//  cert fails here rather than as a mysterious bootstrapping failure
+		nodeId, err := certProvider.GetNodeId()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred computing the node ID of genesis staker %v", i)
+		}
+		if staker.NodeID != nodeId {
+			return stacktrace.NewError("Genesis staker %v has node ID %v, but its cert gives node ID %v", i, staker.NodeID, nodeId)
+		}
+		bootNodeCertProviders = append(bootNodeCertProviders, certProvider)
+		bootNodeIds = append(bootNodeIds, nodeId)
 	}
 
 	// Add boot node configs
-	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
+	for i := 0; i < len(genesisStakers); i++ {
 		configId := networks.ConfigurationID(bootNodeConfigIdPrefix + strconv.Itoa(i))
 
-		certString := localNetGenesisStakers[i].TlsCert
-		keyString := localNetGenesisStakers[i].PrivateKey
-
-		certBytes := bytes.NewBufferString(certString)
-		keyBytes := bytes.NewBufferString(keyString)
-
 		initializerCore := ava_services.NewGeckoServiceInitializerCore(
 			loader.bootstrapperSnowSampleSize,
 			loader.bootstrapperSnowQuorumSize,
 			loader.isStaking,
-			make(map[string]string), // No additional CLI args for the bootstrapper nodes
-			bootNodeIds[0:i],        // Only the node IDs of the already-started nodes
-			cert_providers.NewStaticGeckoCertProvider(*keyBytes, *certBytes),
+			loader.genesisConfig.NetworkID,
+			ava_services.GeckoNodeConfig{}, // No additional flags for the bootstrapper nodes
+			bootNodeIds[0:i],               // Only the node IDs of the already-started nodes
+			bootNodeCertProviders[i],
 			loader.bootNodeLogLevel,
+		).WithIdentityRegistry(loader.identityRegistry)
+		availabilityCheckerCore := ava_services.NewGeckoServiceAvailabilityCheckerCore(
+			make([]string, 0), // Boot nodes only need to have bootstrapped the default chains
 		)
-		availabilityCheckerCore := ava_services.GeckoServiceAvailabilityCheckerCore{}
 
 		if err := builder.AddConfiguration(configId, loader.bootNodeImage, initializerCore, availabilityCheckerCore); err != nil {
 			return stacktrace.Propagate(err, "An error occurred adding bootstrapper node with config ID %v", configId)
@@ -208,19 +639,23 @@ func (loader TestGeckoNetworkLoader) ConfigureNetwork(builder *networks.ServiceN
 
 	// Add user-custom configs
 	for configId, configParams := range loader.serviceConfigs {
-		certProvider := cert_providers.NewRandomGeckoCertProvider(configParams.varyCerts)
+		certProvider := configParams.certProvider
+		if certProvider == nil {
+			certProvider = loader.getServiceCertProvider(configId, configParams.varyCerts)
+		}
 		imageName := configParams.imageName
 
 		initializerCore := ava_services.NewGeckoServiceInitializerCore(
 			configParams.snowSampleSize,
 			configParams.snowQuorumSize,
 			loader.isStaking,
-			configParams.additionalCLIArgs,
+			loader.genesisConfig.NetworkID,
+			configParams.nodeConfig,
 			bootNodeIds,
 			certProvider,
 			configParams.serviceLogLevel,
-		)
-		availabilityCheckerCore := ava_services.GeckoServiceAvailabilityCheckerCore{}
+		).WithIdentityRegistry(loader.identityRegistry)
+		availabilityCheckerCore := ava_services.NewGeckoServiceAvailabilityCheckerCore(configParams.additionalBootstrappedChains)
 		if err := builder.AddConfiguration(configId, imageName, initializerCore, availabilityCheckerCore); err != nil {
 			return stacktrace.Propagate(err, "An error occurred adding Gecko node configuration with ID %v", configId)
 		}
@@ -228,6 +663,17 @@ func (loader TestGeckoNetworkLoader) ConfigureNetwork(builder *networks.ServiceN
 	return nil
 }
 
+func (loader TestGeckoNetworkLoader) getServiceCertProvider(configId networks.ConfigurationID, varyCerts bool) cert_providers.GeckoCertProvider {
+	if loader.certSeed == nil {
+		return cert_providers.NewRandomGeckoCertProvider(varyCerts)
+	}
+	// Each configuration gets its own seed so that two configurations don't hand out the same certs
+	configIdHash := fnv.New64a()
+	configIdHash.Write([]byte(configId))
+	configSeed := *loader.certSeed ^ int64(configIdHash.Sum64())
+	return cert_providers.NewSeededGeckoCertProvider(configSeed, varyCerts)
+}
+
 /*
 Initializes the Gecko test network, spinning up the correct number of bootstrapper nodes and then the user-requested nodes.
 
@@ -239,10 +685,10 @@ func (loader TestGeckoNetworkLoader) InitializeNetwork(network *networks.Service
 
 	// Add the bootstrapper nodes
 	bootstrapperServiceIds := make(map[networks.ServiceID]bool)
-	for i := 0; i < len(DefaultLocalNetGenesisConfig.Stakers); i++ {
+	for i := 0; i < len(loader.genesisConfig.Stakers); i++ {
 		configId := networks.ConfigurationID(bootNodeConfigIdPrefix + strconv.Itoa(i))
 		serviceId := networks.ServiceID(bootNodeServiceIdPrefix + strconv.Itoa(i))
-		checker, err := network.AddService(configId, serviceId, bootstrapperServiceIds)
+		checker, err := loader.addService(network, configId, serviceId, bootstrapperServiceIds)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "Error occurred when adding boot node with ID %v and config ID %v", serviceId, configId)
 		}
@@ -250,22 +696,102 @@ func (loader TestGeckoNetworkLoader) InitializeNetwork(network *networks.Service
 		// TODO the first node should have zero dependencies and the rest should
 		// have only the first node as a dependency
 		bootstrapperServiceIds[serviceId] = true
+		loader.serviceConfigIds.set(serviceId, configId)
 		availabilityCheckers[serviceId] = *checker
 	}
 
 	// Additional user defined nodes
 	for serviceId, configId := range loader.desiredServiceConfig {
-		checker, err := network.AddService(configId, serviceId, bootstrapperServiceIds)
+		checker, err := loader.addService(network, configId, serviceId, bootstrapperServiceIds)
 		if err != nil {
 			return nil, stacktrace.Propagate(err, "Error occurred when adding non-boot node with ID %v and config ID %v", serviceId, configId)
 		}
+		loader.serviceConfigIds.set(serviceId, configId)
 		availabilityCheckers[serviceId] = *checker
 	}
 	return availabilityCheckers, nil
 }
 
+// Adds a service to the network through the identity registry, so that the identity of its node gets recorded
+func (loader TestGeckoNetworkLoader) addService(
+	network *networks.ServiceNetwork,
+	configId networks.ConfigurationID,
+	serviceId networks.ServiceID,
+	dependencies map[networks.ServiceID]bool) (*services.ServiceAvailabilityChecker, error) {
+	var checker *services.ServiceAvailabilityChecker
+	err := loader.identityRegistry.StartService(string(serviceId), nil, func() error {
+		var err error
+		checker, err = network.AddService(configId, serviceId, dependencies)
+		return err
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred adding service %v with configuration %v", serviceId, configId)
+	}
+	return checker, nil
+}
+
 func (loader TestGeckoNetworkLoader) WrapNetwork(network *networks.ServiceNetwork) (networks.Network, error) {
+	commandRunner := NewDockerSidecarCommandRunner(DEFAULT_NETWORK_TOOLS_IMAGE)
 	return TestGeckoNetwork{
-		svcNetwork: network,
+		svcNetwork:      network,
+		genesisConfig:   loader.genesisConfig,
+		ctx:             context.Background(),
+		partitioner:     newNetworkPartitioner(commandRunner),
+		linkConditioner: newLinkConditioner(commandRunner),
+
+		containerLifecycleManager: DockerCliContainerLifecycleManager{},
+		identityRegistry:          loader.identityRegistry,
+		serviceConfigs:            loader.serviceConfigs,
+		serviceConfigIds:          loader.serviceConfigIds,
 	}, nil
 }
+
+// ============== Service Config Tracker ======================
+// Tracks the configuration that each service in the network was started with
+type serviceConfigTracker struct {
+	// Guards configIds, which is shared between all copies of the network
+	mutex *sync.Mutex
+
+	configIds map[networks.ServiceID]networks.ConfigurationID
+}
+
+func newServiceConfigTracker() *serviceConfigTracker {
+	return &serviceConfigTracker{
+		mutex:     &sync.Mutex{},
+		configIds: make(map[networks.ServiceID]networks.ConfigurationID),
+	}
+}
+
+func (tracker *serviceConfigTracker) set(serviceId networks.ServiceID, configId networks.ConfigurationID) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	tracker.configIds[serviceId] = configId
+}
+
+func (tracker *serviceConfigTracker) get(serviceId networks.ServiceID) (networks.ConfigurationID, bool) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	configId, found := tracker.configIds[serviceId]
+	return configId, found
+}
+
+func (tracker *serviceConfigTracker) getServiceIds(configId networks.ConfigurationID) []networks.ServiceID {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	result := make([]networks.ServiceID, 0)
+	for serviceId, serviceConfigId := range tracker.configIds {
+		if serviceConfigId == configId {
+			result = append(result, serviceId)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+	return result
+}
+
+func (tracker *serviceConfigTracker) forget(serviceId networks.ServiceID) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	delete(tracker.configIds, serviceId)
+}
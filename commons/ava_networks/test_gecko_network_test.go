@@ -0,0 +1,41 @@
+package ava_networks
+
+import (
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestartedServicesWaitForTheirConfigsChains(t *testing.T) {
+	subnetConfigId := networks.ConfigurationID("subnet-config")
+	serviceConfigs := map[networks.ConfigurationID]TestGeckoNetworkServiceConfig{
+		subnetConfigId: *NewTestGeckoNetworkServiceConfig(true, ava_services.LOG_LEVEL_DEBUG, "image", 2, 2, ava_services.GeckoNodeConfig{}, []string{"subnet-chain"}),
+	}
+	network := TestGeckoNetwork{
+		serviceConfigs:   serviceConfigs,
+		serviceConfigIds: newServiceConfigTracker(),
+	}
+	network.serviceConfigIds.set("subnet-node", subnetConfigId)
+	network.serviceConfigIds.set("boot-node-0", "boot-node-config-0")
+
+	assert.Equal(t, []string{"subnet-chain"}, network.getAdditionalBootstrappedChains("subnet-node"))
+	assert.Empty(t, network.getAdditionalBootstrappedChains("boot-node-0"))
+	assert.Empty(t, network.getAdditionalBootstrappedChains("unknown-node"))
+
+	network.serviceConfigIds.forget("subnet-node")
+	assert.Empty(t, network.getAdditionalBootstrappedChains("subnet-node"))
+}
+
+func TestGetServiceIdsWithConfig(t *testing.T) {
+	network := TestGeckoNetwork{
+		serviceConfigIds: newServiceConfigTracker(),
+	}
+	network.serviceConfigIds.set("byzantine-node-1", "byzantine-config")
+	network.serviceConfigIds.set("byzantine-node-0", "byzantine-config")
+	network.serviceConfigIds.set("normal-node", "normal-config")
+
+	assert.Equal(t, []networks.ServiceID{"byzantine-node-0", "byzantine-node-1"}, network.GetServiceIdsWithConfig("byzantine-config"))
+	assert.Empty(t, network.GetServiceIdsWithConfig("unknown-config"))
+}
@@ -0,0 +1,207 @@
+package cert_providers
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// In a cert pool directory, each cert file must have a key file with the same name alongside it
+	certFileExtension = ".crt"
+	keyFileExtension  = ".key"
+
+	// Matches both PKCS#1 ("RSA PRIVATE KEY") and PKCS#8 ("PRIVATE KEY") keys
+	privateKeyPreambleSuffix = "PRIVATE KEY"
+)
+
+type certAndKey struct {
+	// Name used to identify the pair in error messages (e.g. the name of the file the pair came from)
+	name    string
+	certPem []byte
+	keyPem  []byte
+}
+
+/*
+A pool of pre-generated cert & key pairs (e.g. fixtures of testnet-style staker identities), loaded from disk so that
+they can be used without being compiled in as Go constants
+*/
+type GeckoCertPool struct {
+	pairs []certAndKey
+}
+
+/*
+Loads a cert pool from a directory where every cert lives in a file ending in '.crt', alongside its key in a file of
+the same name ending in '.key' (e.g. staker1.crt & staker1.key). Files with other extensions are ignored, and the pairs
+are ordered by filename.
+
+Args:
+	dirpath: The directory to load the certs & keys from
+*/
+func LoadGeckoCertPoolFromDirectory(dirpath string) (*GeckoCertPool, error) {
+	fileInfos, err := ioutil.ReadDir(dirpath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not read cert pool directory %v", dirpath)
+	}
+
+	// ReadDir returns files sorted by name, but we sort anyways so the order doesn't depend on that
+	certFilenames := []string{}
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() && strings.HasSuffix(fileInfo.Name(), certFileExtension) {
+			certFilenames = append(certFilenames, fileInfo.Name())
+		}
+	}
+	sort.Strings(certFilenames)
+
+	pairs := []certAndKey{}
+	for _, certFilename := range certFilenames {
+		name := strings.TrimSuffix(certFilename, certFileExtension)
+		certPem, err := ioutil.ReadFile(path.Join(dirpath, certFilename))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not read cert file %v", certFilename)
+		}
+		keyFilename := name + keyFileExtension
+		keyPem, err := ioutil.ReadFile(path.Join(dirpath, keyFilename))
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not read key file %v for cert file %v", keyFilename, certFilename)
+		}
+		pairs = append(pairs, certAndKey{name: name, certPem: certPem, keyPem: keyPem})
+	}
+	return newGeckoCertPool(pairs)
+}
+
+/*
+Loads a cert pool from a single PEM bundle file, in which each cert block is immediately followed or preceded by the
+block of its private key
+
+Args:
+	bundleFilepath: The PEM bundle file to load the certs & keys from
+*/
+func LoadGeckoCertPoolFromBundle(bundleFilepath string) (*GeckoCertPool, error) {
+	bundleBytes, err := ioutil.ReadFile(bundleFilepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not read cert bundle file %v", bundleFilepath)
+	}
+	pairs, err := parseCertBundle(bundleBytes)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not parse cert bundle file %v", bundleFilepath)
+	}
+	return newGeckoCertPool(pairs)
+}
+
+func parseCertBundle(bundleBytes []byte) ([]certAndKey, error) {
+	blocks := []*pem.Block{}
+	remaining := bundleBytes
+	for {
+		var block *pem.Block
+		block, remaining = pem.Decode(remaining)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	if len(bytes.TrimSpace(remaining)) > 0 {
+		return nil, stacktrace.NewError("Bundle has trailing data which isn't a PEM block")
+	}
+	if len(blocks)%2 != 0 {
+		return nil, stacktrace.NewError("Bundle has an odd number of PEM blocks (%v), so can't be made up of cert & key pairs", len(blocks))
+	}
+
+	pairs := []certAndKey{}
+	for i := 0; i < len(blocks); i += 2 {
+		first := blocks[i]
+		second := blocks[i+1]
+		var certBlock, keyBlock *pem.Block
+		if first.Type == certificatePreamble && strings.HasSuffix(second.Type, privateKeyPreambleSuffix) {
+			certBlock, keyBlock = first, second
+		} else if strings.HasSuffix(first.Type, privateKeyPreambleSuffix) && second.Type == certificatePreamble {
+			certBlock, keyBlock = second, first
+		} else {
+			return nil, stacktrace.NewError(
+				"PEM blocks %v and %v have types '%v' and '%v', but each cert must be paired with a private key",
+				i,
+				i+1,
+				first.Type,
+				second.Type)
+		}
+		pairs = append(pairs, certAndKey{
+			name:    "bundle pair " + strconv.Itoa(i/2),
+			certPem: pem.EncodeToMemory(certBlock),
+			keyPem:  pem.EncodeToMemory(keyBlock),
+		})
+	}
+	return pairs, nil
+}
+
+func newGeckoCertPool(pairs []certAndKey) (*GeckoCertPool, error) {
+	if len(pairs) == 0 {
+		return nil, stacktrace.NewError("Cert pool must contain at least one cert & key pair")
+	}
+	for _, pair := range pairs {
+		// Catches both unparseable PEM and keys that don't belong to their cert, which Gecko would otherwise only
+		//  report when it tries to start
+		if _, err := tls.X509KeyPair(pair.certPem, pair.keyPem); err != nil {
+			return nil, stacktrace.Propagate(err, "The key of cert & key pair '%v' doesn't match its cert", pair.name)
+		}
+	}
+	return &GeckoCertPool{pairs: pairs}, nil
+}
+
+// Gets the number of cert & key pairs in the pool
+func (pool GeckoCertPool) Size() int {
+	return len(pool.pairs)
+}
+
+/*
+Gets a provider which always returns the cert & key at the given index of the pool
+
+Args:
+	index: The index of the pair in the pool
+*/
+func (pool GeckoCertPool) GetIndexedProvider(index int) (*StaticGeckoCertProvider, error) {
+	if index < 0 || index >= len(pool.pairs) {
+		return nil, stacktrace.NewError("Index %v is out of range for cert pool of size %v", index, len(pool.pairs))
+	}
+	pair := pool.pairs[index]
+	return NewStaticGeckoCertProvider(*bytes.NewBuffer(pair.keyPem), *bytes.NewBuffer(pair.certPem)), nil
+}
+
+// Gets a provider which hands out the pool's certs & keys in order, wrapping back to the start once all have been used
+func (pool GeckoCertPool) GetRoundRobinProvider() *RoundRobinGeckoCertProvider {
+	return &RoundRobinGeckoCertProvider{
+		pool:      pool,
+		nextIndex: 0,
+	}
+}
+
+/*
+A provider which hands out the certs of a GeckoCertPool in order, wrapping back to the start once all have been used
+
+NOTE: If the pool has fewer certs than the services using the provider, services will share node IDs!
+*/
+type RoundRobinGeckoCertProvider struct {
+	pool      GeckoCertPool
+	nextIndex int
+}
+
+func (r *RoundRobinGeckoCertProvider) GetCertAndKey() (certPemBytes bytes.Buffer, keyPemBytes bytes.Buffer, err error) {
+	pair := r.pool.pairs[r.nextIndex]
+	r.nextIndex = (r.nextIndex + 1) % len(r.pool.pairs)
+	return *bytes.NewBuffer(pair.certPem), *bytes.NewBuffer(pair.keyPem), nil
+}
+
+func (r *RoundRobinGeckoCertProvider) GetNodeId() (string, error) {
+	pair := r.pool.pairs[r.nextIndex]
+	nodeId, err := ComputeNodeId(*bytes.NewBuffer(pair.certPem))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Could not compute the node ID of cert & key pair '%v'", pair.name)
+	}
+	return nodeId, nil
+}
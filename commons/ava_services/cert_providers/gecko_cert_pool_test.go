@@ -0,0 +1,116 @@
+package cert_providers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func generateTestPairs(t *testing.T, numPairs int) []certAndKey {
+	pairs := []certAndKey{}
+	for i := 0; i < numPairs; i++ {
+		certPem, keyPem, err := generateCertAndKey(int64(i))
+		assert.NoError(t, err)
+		pairs = append(pairs, certAndKey{certPem: certPem.Bytes(), keyPem: keyPem.Bytes()})
+	}
+	return pairs
+}
+
+func writeTestFile(t *testing.T, filepath string, contents []byte) {
+	err := ioutil.WriteFile(filepath, contents, 0644)
+	assert.NoError(t, err)
+}
+
+func TestLoadPoolFromDirectory(t *testing.T) {
+	pairs := generateTestPairs(t, 2)
+	dirpath, err := ioutil.TempDir("", "cert-pool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirpath)
+	writeTestFile(t, path.Join(dirpath, "staker1.crt"), pairs[0].certPem)
+	writeTestFile(t, path.Join(dirpath, "staker1.key"), pairs[0].keyPem)
+	writeTestFile(t, path.Join(dirpath, "staker2.crt"), pairs[1].certPem)
+	writeTestFile(t, path.Join(dirpath, "staker2.key"), pairs[1].keyPem)
+	writeTestFile(t, path.Join(dirpath, "README.md"), []byte("Not a cert"))
+
+	pool, err := LoadGeckoCertPoolFromDirectory(dirpath)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pool.Size())
+
+	provider, err := pool.GetIndexedProvider(1)
+	assert.NoError(t, err)
+	certPem, keyPem, err := provider.GetCertAndKey()
+	assert.NoError(t, err)
+	assert.Equal(t, pairs[1].certPem, certPem.Bytes())
+	assert.Equal(t, pairs[1].keyPem, keyPem.Bytes())
+
+	_, err = pool.GetIndexedProvider(2)
+	assert.Error(t, err, "Out-of-range index should be rejected")
+}
+
+func TestLoadPoolFromDirectoryMissingKey(t *testing.T) {
+	pairs := generateTestPairs(t, 1)
+	dirpath, err := ioutil.TempDir("", "cert-pool")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dirpath)
+	writeTestFile(t, path.Join(dirpath, "staker1.crt"), pairs[0].certPem)
+
+	_, err = LoadGeckoCertPoolFromDirectory(dirpath)
+	assert.Error(t, err)
+}
+
+func TestLoadPoolFromBundle(t *testing.T) {
+	pairs := generateTestPairs(t, 2)
+	bundle := bytes.Buffer{}
+	bundle.Write(pairs[0].certPem)
+	bundle.Write(pairs[0].keyPem)
+	// Keys may come before their certs
+	bundle.Write(pairs[1].keyPem)
+	bundle.Write(pairs[1].certPem)
+	bundleFile, err := ioutil.TempFile("", "cert-bundle")
+	assert.NoError(t, err)
+	defer os.Remove(bundleFile.Name())
+	writeTestFile(t, bundleFile.Name(), bundle.Bytes())
+
+	pool, err := LoadGeckoCertPoolFromBundle(bundleFile.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, pool.Size())
+
+	provider := pool.GetRoundRobinProvider()
+	for i := 0; i < 3; i++ {
+		expectedPair := pairs[i%2]
+		expectedNodeId, err := ComputeNodeId(*bytes.NewBuffer(expectedPair.certPem))
+		assert.NoError(t, err)
+		nodeId, err := provider.GetNodeId()
+		assert.NoError(t, err)
+		assert.Equal(t, expectedNodeId, nodeId)
+
+		certPem, keyPem, err := provider.GetCertAndKey()
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPair.certPem, certPem.Bytes(), "Round robin provider should wrap around at the end of the pool")
+		assert.Equal(t, expectedPair.keyPem, keyPem.Bytes())
+	}
+}
+
+func TestBundleRejectsUnpairedBlocks(t *testing.T) {
+	pairs := generateTestPairs(t, 2)
+	bundle := bytes.Buffer{}
+	bundle.Write(pairs[0].certPem)
+	bundle.Write(pairs[1].certPem)
+
+	_, err := parseCertBundle(bundle.Bytes())
+	assert.Error(t, err, "Two certs in a row should be rejected")
+}
+
+func TestPoolRejectsMismatchedKey(t *testing.T) {
+	pairs := generateTestPairs(t, 2)
+	mismatchedPairs := []certAndKey{
+		{name: "mismatched", certPem: pairs[0].certPem, keyPem: pairs[1].keyPem},
+	}
+
+	_, err := newGeckoCertPool(mismatchedPairs)
+	assert.Error(t, err)
+}
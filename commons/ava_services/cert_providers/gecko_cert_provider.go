@@ -1,7 +1,38 @@
 package cert_providers
 
-import "bytes"
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/palantir/stacktrace"
+)
 
 type GeckoCertProvider interface {
 	GetCertAndKey() (certPemBytes bytes.Buffer, keyPemBytes bytes.Buffer, err error)
+
+	// Gets the ID that a Gecko node will have if it uses the cert returned by the next call to GetCertAndKey
+	GetNodeId() (string, error)
+}
+
+/*
+Computes the ID that a Gecko node using the given staking cert will have, the same way Gecko does: the CB58-encoded
+RIPEMD-160 hash of the SHA-256 hash of the cert's DER bytes
+
+Args:
+	certPemBytes: The PEM-encoded cert
+*/
+func ComputeNodeId(certPemBytes bytes.Buffer) (string, error) {
+	block, _ := pem.Decode(certPemBytes.Bytes())
+	if block == nil || block.Type != certificatePreamble {
+		return "", stacktrace.NewError("Could not decode a PEM-encoded certificate from the given bytes")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Could not parse certificate")
+	}
+	nodeId := ids.NewShortID(hashing.ComputeHash160Array(hashing.ComputeHash256(cert.Raw)))
+	return nodeId.String(), nil
 }
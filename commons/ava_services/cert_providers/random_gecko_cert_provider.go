@@ -40,8 +40,12 @@ var rootCert = x509.Certificate{
 A provider for Gecko service certs, with all certs signed by the same root CA
  */
 type RandomGeckoCertProvider struct {
-	nextSerialNumber int64
 	varyCerts bool
+
+	// The cert & key that the next call to GetCertAndKey will return, generated on demand so that the node ID of the
+	//  next cert can be known before it's handed out
+	nextCertPem *bytes.Buffer
+	nextKeyPem  *bytes.Buffer
 }
 
 /*
@@ -52,25 +56,63 @@ Args:
  */
 func NewRandomGeckoCertProvider(varyCerts bool) *RandomGeckoCertProvider {
 	return &RandomGeckoCertProvider{
-		nextSerialNumber: mathrand.Int63(),
 		varyCerts: varyCerts,
 	}
 }
 
 func (r *RandomGeckoCertProvider) GetCertAndKey() (certPemBytes bytes.Buffer, keyPemBytes bytes.Buffer, err error) {
-	serialNum := r.nextSerialNumber
+	if err := r.ensureNextCertGenerated(); err != nil {
+		return bytes.Buffer{}, bytes.Buffer{}, stacktrace.Propagate(err, "Failed to generate cert.")
+	}
+	certPem := *r.nextCertPem
+	keyPem := *r.nextKeyPem
 	if (r.varyCerts) {
-		r.nextSerialNumber = mathrand.Int63()
+		r.nextCertPem = nil
+		r.nextKeyPem = nil
+	}
+	return certPem, keyPem, nil
+}
+
+func (r *RandomGeckoCertProvider) GetNodeId() (string, error) {
+	if err := r.ensureNextCertGenerated(); err != nil {
+		return "", stacktrace.Propagate(err, "Failed to generate cert.")
+	}
+	nodeId, err := ComputeNodeId(*r.nextCertPem)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to compute node ID of generated cert.")
+	}
+	return nodeId, nil
+}
+
+func (r *RandomGeckoCertProvider) ensureNextCertGenerated() error {
+	if r.nextCertPem != nil {
+		return nil
+	}
+	certPem, keyPem, err := generateCertAndKey(mathrand.Int63())
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to generate random cert and key.")
 	}
-	serviceCert := getServiceCert(serialNum)
+	r.nextCertPem = certPem
+	r.nextKeyPem = keyPem
+	return nil
+}
+
+// ================= Helper functions ===================
+func generateCertAndKey(serialNum int64) (*bytes.Buffer, *bytes.Buffer, error) {
+	serviceCert := getServiceCert(serialNum, time.Now())
 
 	certPrivKey, err := rsa.GenerateKey(rand.Reader, 4096)
 	if err != nil {
-		return bytes.Buffer{}, bytes.Buffer{}, stacktrace.Propagate(err, "Failed to generate random private key.")
+		return nil, nil, stacktrace.Propagate(err, "Failed to generate random private key.")
 	}
+	return signAndEncodeCert(serviceCert, certPrivKey)
+}
+
+// Signs the given service cert with the root CA, and PEM-encodes it along with its private key
+func signAndEncodeCert(serviceCert *x509.Certificate, certPrivKey *rsa.PrivateKey) (*bytes.Buffer, *bytes.Buffer, error) {
 	certBytes, err := x509.CreateCertificate(rand.Reader, serviceCert, &rootCert, &(certPrivKey.PublicKey), certPrivKey)
 	if err != nil {
-		return bytes.Buffer{}, bytes.Buffer{}, stacktrace.Propagate(err, "Failed to sign service cert with cert authority.")
+		return nil, nil, stacktrace.Propagate(err, "Failed to sign service cert with cert authority.")
 	}
 	certPEM := new(bytes.Buffer)
 	pem.Encode(certPEM, &pem.Block{
@@ -83,11 +125,10 @@ func (r *RandomGeckoCertProvider) GetCertAndKey() (certPemBytes bytes.Buffer, ke
 		Type:  privateKeyPreamble,
 		Bytes: x509.MarshalPKCS1PrivateKey(certPrivKey),
 	})
-	return *certPEM, *certPrivKeyPEM, nil
+	return certPEM, certPrivKeyPEM, nil
 }
 
-// ================= Helper functions ===================
-func getServiceCert(serialNumber int64) *x509.Certificate {
+func getServiceCert(serialNumber int64, notBefore time.Time) *x509.Certificate {
 	cert := &x509.Certificate{
 		SerialNumber: big.NewInt(serialNumber),
 		Subject: pkix.Name{
@@ -98,8 +139,8 @@ func getServiceCert(serialNumber int64) *x509.Certificate {
 			StreetAddress: []string{""},
 			PostalCode:    []string{""},
 		},
-		NotBefore:    time.Now(),
-		NotAfter:     time.Now().AddDate(10, 0, 0),
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.AddDate(10, 0, 0),
 		SubjectKeyId: []byte{1, 2, 3, 4, 6},
 		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 		KeyUsage:     x509.KeyUsageDigitalSignature,
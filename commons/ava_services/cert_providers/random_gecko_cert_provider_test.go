@@ -0,0 +1,41 @@
+package cert_providers
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeIdMatchesNextCert(t *testing.T) {
+	provider := NewRandomGeckoCertProvider(true)
+
+	expectedNodeId, err := provider.GetNodeId()
+	assert.NoError(t, err)
+	certPem, _, err := provider.GetCertAndKey()
+	assert.NoError(t, err)
+	actualNodeId, err := ComputeNodeId(certPem)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedNodeId, actualNodeId)
+
+	nextNodeId, err := provider.GetNodeId()
+	assert.NoError(t, err)
+	assert.NotEqual(t, expectedNodeId, nextNodeId, "A varying provider should give a new cert after each GetCertAndKey")
+}
+
+func TestNonVaryingProviderReturnsSameCert(t *testing.T) {
+	provider := NewRandomGeckoCertProvider(false)
+
+	firstCertPem, firstKeyPem, err := provider.GetCertAndKey()
+	assert.NoError(t, err)
+	secondCertPem, secondKeyPem, err := provider.GetCertAndKey()
+	assert.NoError(t, err)
+	assert.Equal(t, firstCertPem.String(), secondCertPem.String())
+	assert.Equal(t, firstKeyPem.String(), secondKeyPem.String())
+}
+
+func TestComputeNodeIdRejectsNonCert(t *testing.T) {
+	provider := NewStaticGeckoCertProvider(*bytes.NewBufferString("key"), *bytes.NewBufferString("not a cert"))
+	_, err := provider.GetNodeId()
+	assert.Error(t, err)
+}
@@ -0,0 +1,192 @@
+package cert_providers
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+	mathrand "math/rand"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	seededKeyBits       = 4096
+	rsaPublicExponent   = 65537
+	primalityTestRounds = 20
+)
+
+// Seeded certs can't use the current time, else the cert bytes (and so the node ID) would change from run to run
+var seededCertNotBefore = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+/*
+A provider for Gecko service certs whose certs & keys are fully determined by a seed and the index of the cert, so that
+a test run can be reproduced with exactly the same node IDs.
+
+NOTE: The keys are generated from a predictable stream, so must only ever be used in tests.
+*/
+type SeededGeckoCertProvider struct {
+	seed      int64
+	varyCerts bool
+
+	// Index of the cert that the next call to GetCertAndKey will return
+	nextIndex int
+
+	// The cert & key at nextIndex, cached because generating them is expensive
+	nextCertPem *bytes.Buffer
+	nextKeyPem  *bytes.Buffer
+}
+
+/*
+Creates a new cert provider whose certs are reproducible from the seed
+
+Args:
+	seed: The seed that determines every cert the provider hands out
+	varyCerts: Whether to produce a different cert on each call to GetCertAndKey (index 0, 1, 2...), or the cert at
+		index 0 every time
+*/
+func NewSeededGeckoCertProvider(seed int64, varyCerts bool) *SeededGeckoCertProvider {
+	return &SeededGeckoCertProvider{
+		seed:      seed,
+		varyCerts: varyCerts,
+		nextIndex: 0,
+	}
+}
+
+func (s *SeededGeckoCertProvider) GetCertAndKey() (certPemBytes bytes.Buffer, keyPemBytes bytes.Buffer, err error) {
+	if err := s.ensureNextCertGenerated(); err != nil {
+		return bytes.Buffer{}, bytes.Buffer{}, stacktrace.Propagate(err, "Failed to generate cert at index %v.", s.nextIndex)
+	}
+	certPem := *s.nextCertPem
+	keyPem := *s.nextKeyPem
+	if s.varyCerts {
+		s.nextIndex++
+		s.nextCertPem = nil
+		s.nextKeyPem = nil
+	}
+	return certPem, keyPem, nil
+}
+
+func (s *SeededGeckoCertProvider) GetNodeId() (string, error) {
+	if err := s.ensureNextCertGenerated(); err != nil {
+		return "", stacktrace.Propagate(err, "Failed to generate cert at index %v.", s.nextIndex)
+	}
+	nodeId, err := ComputeNodeId(*s.nextCertPem)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to compute node ID of cert at index %v.", s.nextIndex)
+	}
+	return nodeId, nil
+}
+
+func (s *SeededGeckoCertProvider) ensureNextCertGenerated() error {
+	if s.nextCertPem != nil {
+		return nil
+	}
+	certPem, keyPem, err := GenerateSeededCertAndKey(s.seed, s.nextIndex)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to generate seeded cert and key.")
+	}
+	s.nextCertPem = certPem
+	s.nextKeyPem = keyPem
+	return nil
+}
+
+/*
+Generates the cert & key at the given index for the given seed; the same seed and index always give the same cert & key
+
+Args:
+	seed: The seed to generate from
+	index: The index of the cert to generate
+*/
+func GenerateSeededCertAndKey(seed int64, index int) (*bytes.Buffer, *bytes.Buffer, error) {
+	random := newSeededReader(seed, index)
+
+	var serialNumBytes [8]byte
+	if _, err := io.ReadFull(random, serialNumBytes[:]); err != nil {
+		return nil, nil, stacktrace.Propagate(err, "Failed to read serial number from seeded stream.")
+	}
+	// Serial numbers must be positive
+	serialNum := int64(binary.BigEndian.Uint64(serialNumBytes[:]) >> 1)
+
+	certPrivKey, err := generateDeterministicRsaKey(random, seededKeyBits)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "Failed to generate seeded private key.")
+	}
+	return signAndEncodeCert(getServiceCert(serialNum, seededCertNotBefore), certPrivKey)
+}
+
+// Gets a stream of bytes which depends only on the seed and index
+func newSeededReader(seed int64, index int) io.Reader {
+	var seedAndIndex [16]byte
+	binary.BigEndian.PutUint64(seedAndIndex[:8], uint64(seed))
+	binary.BigEndian.PutUint64(seedAndIndex[8:], uint64(index))
+	hash := sha256.Sum256(seedAndIndex[:])
+	return mathrand.New(mathrand.NewSource(int64(binary.BigEndian.Uint64(hash[:8]))))
+}
+
+/*
+The standard library's rsa.GenerateKey deliberately consumes a random number of bytes from the stream it's given so that
+callers can't depend on its output being deterministic, so we need our own key generation.
+*/
+func generateDeterministicRsaKey(random io.Reader, bits int) (*rsa.PrivateKey, error) {
+	exponent := big.NewInt(rsaPublicExponent)
+	one := big.NewInt(1)
+	for {
+		p, err := generateDeterministicPrime(random, bits/2)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to generate first prime.")
+		}
+		q, err := generateDeterministicPrime(random, bits/2)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to generate second prime.")
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		modulus := new(big.Int).Mul(p, q)
+		if modulus.BitLen() != bits {
+			continue
+		}
+		totient := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		privateExponent := new(big.Int).ModInverse(exponent, totient)
+		if privateExponent == nil {
+			// The public exponent isn't coprime with the totient, so these primes can't be used
+			continue
+		}
+
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{
+				N: modulus,
+				E: rsaPublicExponent,
+			},
+			D:      privateExponent,
+			Primes: []*big.Int{p, q},
+		}
+		if err := key.Validate(); err != nil {
+			return nil, stacktrace.Propagate(err, "Generated an invalid RSA key; this is a code bug")
+		}
+		key.Precompute()
+		return key, nil
+	}
+}
+
+func generateDeterministicPrime(random io.Reader, bits int) (*big.Int, error) {
+	candidateBytes := make([]byte, bits/8)
+	for {
+		if _, err := io.ReadFull(random, candidateBytes); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to read prime candidate from seeded stream.")
+		}
+		// Setting the top two bits guarantees that the product of two such primes has exactly twice as many bits, and
+		//  setting the bottom bit makes the candidate odd
+		candidateBytes[0] |= 0xC0
+		candidateBytes[len(candidateBytes)-1] |= 1
+		candidate := new(big.Int).SetBytes(candidateBytes)
+		if candidate.ProbablyPrime(primalityTestRounds) {
+			return candidate, nil
+		}
+	}
+}
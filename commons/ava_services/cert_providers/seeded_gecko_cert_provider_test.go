@@ -0,0 +1,45 @@
+package cert_providers
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeededCertsAreReproducible(t *testing.T) {
+	firstProvider := NewSeededGeckoCertProvider(42, true)
+	secondProvider := NewSeededGeckoCertProvider(42, true)
+	for i := 0; i < 2; i++ {
+		firstNodeId, err := firstProvider.GetNodeId()
+		assert.NoError(t, err)
+		secondNodeId, err := secondProvider.GetNodeId()
+		assert.NoError(t, err)
+		assert.Equal(t, firstNodeId, secondNodeId, "Providers with the same seed should give the same node ID at index %v", i)
+
+		firstCertPem, firstKeyPem, err := firstProvider.GetCertAndKey()
+		assert.NoError(t, err)
+		secondCertPem, secondKeyPem, err := secondProvider.GetCertAndKey()
+		assert.NoError(t, err)
+		assert.Equal(t, firstCertPem.String(), secondCertPem.String())
+		assert.Equal(t, firstKeyPem.String(), secondKeyPem.String())
+	}
+}
+
+func TestSeededCertsDifferBySeedAndIndex(t *testing.T) {
+	seed42Index0, _, err := GenerateSeededCertAndKey(42, 0)
+	assert.NoError(t, err)
+	seed42Index1, _, err := GenerateSeededCertAndKey(42, 1)
+	assert.NoError(t, err)
+	seed43Index0, _, err := GenerateSeededCertAndKey(43, 0)
+	assert.NoError(t, err)
+	assert.NotEqual(t, seed42Index0.String(), seed42Index1.String())
+	assert.NotEqual(t, seed42Index0.String(), seed43Index0.String())
+}
+
+func TestSeededKeyMatchesCert(t *testing.T) {
+	certPem, keyPem, err := GenerateSeededCertAndKey(42, 0)
+	assert.NoError(t, err)
+	_, err = tls.X509KeyPair(certPem.Bytes(), keyPem.Bytes())
+	assert.NoError(t, err, "Seeded key should be usable with its cert for TLS")
+}
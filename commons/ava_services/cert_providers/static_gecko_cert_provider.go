@@ -1,6 +1,10 @@
 package cert_providers
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/palantir/stacktrace"
+)
 
 type StaticGeckoCertProvider struct {
 	key bytes.Buffer
@@ -15,3 +19,10 @@ func (s StaticGeckoCertProvider) GetCertAndKey() (certPemBytes bytes.Buffer, key
 	return s.cert, s.key, nil
 }
 
+func (s StaticGeckoCertProvider) GetNodeId() (string, error) {
+	nodeId, err := ComputeNodeId(s.cert)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Could not compute the node ID of the static cert")
+	}
+	return nodeId, nil
+}
@@ -0,0 +1,377 @@
+package ava_services
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+)
+
+// ========= Byzantine Behavior Enum ========================
+// Behaviors that the byzantine Gecko image can be told to exhibit; normal Gecko images don't understand the flag
+type GeckoByzantineBehavior string
+
+const (
+	BYZANTINE_BEHAVIOR_CHIT_SPAMMER           GeckoByzantineBehavior = "chit-spammer"
+	BYZANTINE_BEHAVIOR_CONFLICTING_TXS_VERTEX GeckoByzantineBehavior = "conflicting-txs-vertex"
+)
+
+var validByzantineBehaviors = map[GeckoByzantineBehavior]bool{
+	BYZANTINE_BEHAVIOR_CHIT_SPAMMER:           true,
+	BYZANTINE_BEHAVIOR_CONFLICTING_TXS_VERTEX: true,
+}
+
+// The log levels that Gecko nodes can be started at
+var ValidLogLevels = map[GeckoLogLevel]bool{
+	LOG_LEVEL_VERBOSE: true,
+	LOG_LEVEL_DEBUG:   true,
+	LOG_LEVEL_INFO:    true,
+}
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+// ========= Node Config ========================
+/*
+The Gecko flags that the tests use, each of which is only passed to the node if it's set. Nil pointers, nil slices, and
+empty strings mean "unset" so that a flag can still be explicitly set to its zero value (e.g. --http-host=, which
+makes the API openly accessible).
+
+The JSON field names are the names of the Gecko flags, so a config can be loaded from a network spec using the same names
+as on the command line.
+*/
+type GeckoNodeConfig struct {
+	// Network
+	PublicIp  string `json:"public-ip,omitempty"`
+	NetworkId string `json:"network-id,omitempty"`
+
+	// HTTP
+	HttpPort *int    `json:"http-port,omitempty"`
+	HttpHost *string `json:"http-host,omitempty"`
+
+	// Staking
+	StakingPort *int `json:"staking-port,omitempty"`
+
+	// Logging
+	LogLevel GeckoLogLevel `json:"log-level,omitempty"`
+	LogDir   string        `json:"log-dir,omitempty"`
+
+	// Snow consensus
+	SnowSampleSize              *int `json:"snow-sample-size,omitempty"`
+	SnowQuorumSize              *int `json:"snow-quorum-size,omitempty"`
+	SnowVirtuousCommitThreshold *int `json:"snow-virtuous-commit-threshold,omitempty"`
+	SnowRogueCommitThreshold    *int `json:"snow-rogue-commit-threshold,omitempty"`
+
+	// Staking TLS
+	StakingTlsEnabled *bool `json:"staking-tls-enabled,omitempty"`
+
+	// Database
+	DbDir string `json:"db-dir,omitempty"`
+
+	StakingTlsCertFile string `json:"staking-tls-cert-file,omitempty"`
+	StakingTlsKeyFile  string `json:"staking-tls-key-file,omitempty"`
+
+	// Bootstrapping; a non-nil empty slice sets the flag to the empty string
+	BootstrapIds []string `json:"bootstrap-ids,omitempty"`
+	BootstrapIps []string `json:"bootstrap-ips,omitempty"`
+
+	// APIs
+	ApiAdminEnabled    *bool `json:"api-admin-enabled,omitempty"`
+	ApiIpcsEnabled     *bool `json:"api-ipcs-enabled,omitempty"`
+	ApiKeystoreEnabled *bool `json:"api-keystore-enabled,omitempty"`
+	ApiMetricsEnabled  *bool `json:"api-metrics-enabled,omitempty"`
+	ApiHealthEnabled   *bool `json:"api-health-enabled,omitempty"`
+
+	// Only understood by the byzantine Gecko image
+	ByzantineBehavior GeckoByzantineBehavior `json:"byzantine-behavior,omitempty"`
+}
+
+// A single set Gecko flag, whose value is a string, int, or bool
+type geckoFlag struct {
+	name  string
+	value interface{}
+}
+
+/*
+Gets the flags set in the config, in the order they're rendered in
+
+NOTE: When adding a field to GeckoNodeConfig, it must be added here and to Merge
+*/
+func (config GeckoNodeConfig) getSetFlags() []geckoFlag {
+	result := []geckoFlag{}
+	addString := func(name string, value string) {
+		if value != "" {
+			result = append(result, geckoFlag{name: name, value: value})
+		}
+	}
+	addStringPtr := func(name string, value *string) {
+		if value != nil {
+			result = append(result, geckoFlag{name: name, value: *value})
+		}
+	}
+	addInt := func(name string, value *int) {
+		if value != nil {
+			result = append(result, geckoFlag{name: name, value: *value})
+		}
+	}
+	addBool := func(name string, value *bool) {
+		if value != nil {
+			result = append(result, geckoFlag{name: name, value: *value})
+		}
+	}
+	addList := func(name string, value []string) {
+		if value != nil {
+			result = append(result, geckoFlag{name: name, value: strings.Join(value, ",")})
+		}
+	}
+
+	addString("public-ip", config.PublicIp)
+	addString("network-id", config.NetworkId)
+	addInt("http-port", config.HttpPort)
+	addStringPtr("http-host", config.HttpHost)
+	addInt("staking-port", config.StakingPort)
+	addString("log-level", string(config.LogLevel))
+	addString("log-dir", config.LogDir)
+	addInt("snow-sample-size", config.SnowSampleSize)
+	addInt("snow-quorum-size", config.SnowQuorumSize)
+	addInt("snow-virtuous-commit-threshold", config.SnowVirtuousCommitThreshold)
+	addInt("snow-rogue-commit-threshold", config.SnowRogueCommitThreshold)
+	addBool("staking-tls-enabled", config.StakingTlsEnabled)
+	addString("db-dir", config.DbDir)
+	addString("staking-tls-cert-file", config.StakingTlsCertFile)
+	addString("staking-tls-key-file", config.StakingTlsKeyFile)
+	addList("bootstrap-ids", config.BootstrapIds)
+	addList("bootstrap-ips", config.BootstrapIps)
+	addBool("api-admin-enabled", config.ApiAdminEnabled)
+	addBool("api-ipcs-enabled", config.ApiIpcsEnabled)
+	addBool("api-keystore-enabled", config.ApiKeystoreEnabled)
+	addBool("api-metrics-enabled", config.ApiMetricsEnabled)
+	addBool("api-health-enabled", config.ApiHealthEnabled)
+	addString("byzantine-behavior", string(config.ByzantineBehavior))
+	return result
+}
+
+// Gets the names of the flags set in the config
+func (config GeckoNodeConfig) GetSetFlagNames() []string {
+	result := []string{}
+	for _, flag := range config.getSetFlags() {
+		result = append(result, flag.name)
+	}
+	return result
+}
+
+/*
+Checks that the values of the set flags are ones Gecko will accept, and that they're consistent with each other
+*/
+func (config GeckoNodeConfig) Validate() error {
+	if config.PublicIp != "" && net.ParseIP(config.PublicIp) == nil {
+		return stacktrace.NewError("Public IP '%v' is not a valid IP address", config.PublicIp)
+	}
+	if err := validatePort("HTTP", config.HttpPort); err != nil {
+		return err
+	}
+	if err := validatePort("staking", config.StakingPort); err != nil {
+		return err
+	}
+	if config.HttpPort != nil && config.StakingPort != nil && *config.HttpPort == *config.StakingPort {
+		return stacktrace.NewError("HTTP port and staking port must be different, but both are %v", *config.HttpPort)
+	}
+	if config.LogLevel != "" && !ValidLogLevels[config.LogLevel] {
+		return stacktrace.NewError("Unrecognized log level '%v'", config.LogLevel)
+	}
+
+	if err := validatePositive("snow sample size", config.SnowSampleSize); err != nil {
+		return err
+	}
+	if err := validatePositive("snow quorum size", config.SnowQuorumSize); err != nil {
+		return err
+	}
+	if err := validatePositive("snow virtuous commit threshold", config.SnowVirtuousCommitThreshold); err != nil {
+		return err
+	}
+	if err := validatePositive("snow rogue commit threshold", config.SnowRogueCommitThreshold); err != nil {
+		return err
+	}
+	if config.SnowSampleSize != nil && config.SnowQuorumSize != nil && *config.SnowQuorumSize > *config.SnowSampleSize {
+		return stacktrace.NewError(
+			"Snow quorum size %v must not be greater than snow sample size %v",
+			*config.SnowQuorumSize,
+			*config.SnowSampleSize)
+	}
+	if config.SnowVirtuousCommitThreshold != nil && config.SnowRogueCommitThreshold != nil &&
+		*config.SnowRogueCommitThreshold < *config.SnowVirtuousCommitThreshold {
+		return stacktrace.NewError(
+			"Snow rogue commit threshold %v must not be less than snow virtuous commit threshold %v",
+			*config.SnowRogueCommitThreshold,
+			*config.SnowVirtuousCommitThreshold)
+	}
+
+	if (config.StakingTlsCertFile == "") != (config.StakingTlsKeyFile == "") {
+		return stacktrace.NewError("Staking TLS cert file and key file must either both be set or both be unset")
+	}
+	if config.StakingTlsEnabled != nil && !*config.StakingTlsEnabled && config.StakingTlsCertFile != "" {
+		return stacktrace.NewError("Staking TLS cert & key files are set, but staking TLS is disabled")
+	}
+	for _, ipPort := range config.BootstrapIps {
+		if _, _, err := net.SplitHostPort(ipPort); err != nil {
+			return stacktrace.Propagate(err, "Bootstrap IP '%v' is not of the form IP:port", ipPort)
+		}
+	}
+	// Gecko pairs bootstrap IDs with bootstrap IPs by position, and needs an ID for every IP when staking
+	isStaking := config.StakingTlsEnabled != nil && *config.StakingTlsEnabled
+	if isStaking && len(config.BootstrapIps) != len(config.BootstrapIds) {
+		return stacktrace.NewError(
+			"%v bootstrap IPs but %v bootstrap IDs are set, which must be paired up when staking",
+			len(config.BootstrapIps),
+			len(config.BootstrapIds))
+	}
+	if len(config.BootstrapIds) > 0 && config.BootstrapIps != nil && len(config.BootstrapIps) > len(config.BootstrapIds) {
+		return stacktrace.NewError(
+			"%v bootstrap IPs are set but only %v bootstrap IDs are set",
+			len(config.BootstrapIps),
+			len(config.BootstrapIds))
+	}
+
+	if config.ByzantineBehavior != "" && !validByzantineBehaviors[config.ByzantineBehavior] {
+		return stacktrace.NewError("Unrecognized byzantine behavior '%v'", config.ByzantineBehavior)
+	}
+	return nil
+}
+
+func validatePort(portDescription string, port *int) error {
+	if port != nil && (*port < minPort || *port > maxPort) {
+		return stacktrace.NewError("The %v port must be between %v and %v, but was %v", portDescription, minPort, maxPort, *port)
+	}
+	return nil
+}
+
+func validatePositive(valueDescription string, value *int) error {
+	if value != nil && *value <= 0 {
+		return stacktrace.NewError("The %v must be positive, but was %v", valueDescription, *value)
+	}
+	return nil
+}
+
+/*
+Combines this config with another, returning an error naming every flag that both configs set so that neither
+silently overrides the other
+
+Args:
+	other: The config to combine with this one
+*/
+func (config GeckoNodeConfig) Merge(other GeckoNodeConfig) (GeckoNodeConfig, error) {
+	ownFlagNames := map[string]bool{}
+	for _, name := range config.GetSetFlagNames() {
+		ownFlagNames[name] = true
+	}
+	conflictingFlagNames := []string{}
+	for _, name := range other.GetSetFlagNames() {
+		if ownFlagNames[name] {
+			conflictingFlagNames = append(conflictingFlagNames, name)
+		}
+	}
+	if len(conflictingFlagNames) > 0 {
+		sort.Strings(conflictingFlagNames)
+		return GeckoNodeConfig{}, stacktrace.NewError(
+			"The following flags are set in both configs: %v",
+			strings.Join(conflictingFlagNames, ", "))
+	}
+
+	result := config
+	if other.PublicIp != "" {
+		result.PublicIp = other.PublicIp
+	}
+	if other.NetworkId != "" {
+		result.NetworkId = other.NetworkId
+	}
+	if other.HttpPort != nil {
+		result.HttpPort = other.HttpPort
+	}
+	if other.HttpHost != nil {
+		result.HttpHost = other.HttpHost
+	}
+	if other.StakingPort != nil {
+		result.StakingPort = other.StakingPort
+	}
+	if other.LogLevel != "" {
+		result.LogLevel = other.LogLevel
+	}
+	if other.LogDir != "" {
+		result.LogDir = other.LogDir
+	}
+	if other.SnowSampleSize != nil {
+		result.SnowSampleSize = other.SnowSampleSize
+	}
+	if other.SnowQuorumSize != nil {
+		result.SnowQuorumSize = other.SnowQuorumSize
+	}
+	if other.SnowVirtuousCommitThreshold != nil {
+		result.SnowVirtuousCommitThreshold = other.SnowVirtuousCommitThreshold
+	}
+	if other.SnowRogueCommitThreshold != nil {
+		result.SnowRogueCommitThreshold = other.SnowRogueCommitThreshold
+	}
+	if other.StakingTlsEnabled != nil {
+		result.StakingTlsEnabled = other.StakingTlsEnabled
+	}
+	if other.DbDir != "" {
+		result.DbDir = other.DbDir
+	}
+	if other.StakingTlsCertFile != "" {
+		result.StakingTlsCertFile = other.StakingTlsCertFile
+	}
+	if other.StakingTlsKeyFile != "" {
+		result.StakingTlsKeyFile = other.StakingTlsKeyFile
+	}
+	if other.BootstrapIds != nil {
+		result.BootstrapIds = other.BootstrapIds
+	}
+	if other.BootstrapIps != nil {
+		result.BootstrapIps = other.BootstrapIps
+	}
+	if other.ApiAdminEnabled != nil {
+		result.ApiAdminEnabled = other.ApiAdminEnabled
+	}
+	if other.ApiIpcsEnabled != nil {
+		result.ApiIpcsEnabled = other.ApiIpcsEnabled
+	}
+	if other.ApiKeystoreEnabled != nil {
+		result.ApiKeystoreEnabled = other.ApiKeystoreEnabled
+	}
+	if other.ApiMetricsEnabled != nil {
+		result.ApiMetricsEnabled = other.ApiMetricsEnabled
+	}
+	if other.ApiHealthEnabled != nil {
+		result.ApiHealthEnabled = other.ApiHealthEnabled
+	}
+	if other.ByzantineBehavior != "" {
+		result.ByzantineBehavior = other.ByzantineBehavior
+	}
+	return result, nil
+}
+
+// Renders the set flags as Gecko CLI args, e.g. "--snow-sample-size=2"
+func (config GeckoNodeConfig) ToCliArgs() []string {
+	result := []string{}
+	for _, flag := range config.getSetFlags() {
+		result = append(result, fmt.Sprintf("--%s=%v", flag.name, flag.value))
+	}
+	return result
+}
+
+// Helpers for setting the pointer fields of GeckoNodeConfig
+func IntFlag(value int) *int {
+	return &value
+}
+
+func BoolFlag(value bool) *bool {
+	return &value
+}
+
+func StringFlag(value string) *string {
+	return &value
+}
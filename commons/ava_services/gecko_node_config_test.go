@@ -0,0 +1,122 @@
+package ava_services
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
+	"github.com/kurtosis-tech/kurtosis/commons/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCliArgsOnlyIncludeSetFlags(t *testing.T) {
+	config := GeckoNodeConfig{
+		HttpHost:          StringFlag(""),
+		SnowSampleSize:    IntFlag(3),
+		BootstrapIds:      []string{},
+		BootstrapIps:      []string{"1.2.3.4:9651", "1.2.3.5:9651"},
+		ApiAdminEnabled:   BoolFlag(false),
+		ByzantineBehavior: BYZANTINE_BEHAVIOR_CHIT_SPAMMER,
+	}
+	expected := []string{
+		"--http-host=",
+		"--snow-sample-size=3",
+		"--bootstrap-ids=",
+		"--bootstrap-ips=1.2.3.4:9651,1.2.3.5:9651",
+		"--api-admin-enabled=false",
+		"--byzantine-behavior=chit-spammer",
+	}
+	assert.Equal(t, expected, config.ToCliArgs())
+	assert.Equal(t, []string{}, GeckoNodeConfig{}.ToCliArgs())
+}
+
+func TestConfigParsesFromFlagNames(t *testing.T) {
+	var config GeckoNodeConfig
+	err := json.Unmarshal([]byte(`{"api-admin-enabled": true, "byzantine-behavior": "conflicting-txs-vertex"}`), &config)
+	assert.NoError(t, err)
+	assert.Equal(t, BoolFlag(true), config.ApiAdminEnabled)
+	assert.Equal(t, BYZANTINE_BEHAVIOR_CONFLICTING_TXS_VERTEX, config.ByzantineBehavior)
+}
+
+func TestValidateRejectsInvalidConfigs(t *testing.T) {
+	invalidConfigs := map[string]GeckoNodeConfig{
+		"bad IP":                      {PublicIp: "not-an-ip"},
+		"port out of range":           {HttpPort: IntFlag(70000)},
+		"same HTTP & staking port":    {HttpPort: IntFlag(9650), StakingPort: IntFlag(9650)},
+		"unknown log level":           {LogLevel: "loud"},
+		"zero sample size":            {SnowSampleSize: IntFlag(0)},
+		"quorum bigger than sample":   {SnowSampleSize: IntFlag(2), SnowQuorumSize: IntFlag(3)},
+		"rogue below virtuous":        {SnowVirtuousCommitThreshold: IntFlag(20), SnowRogueCommitThreshold: IntFlag(10)},
+		"cert without key":            {StakingTlsCertFile: "/shared/staking.crt"},
+		"cert with staking disabled":  {StakingTlsEnabled: BoolFlag(false), StakingTlsCertFile: "/shared/staking.crt", StakingTlsKeyFile: "/shared/staking.key"},
+		"bootstrap IP without port":   {BootstrapIps: []string{"1.2.3.4"}},
+		"more bootstrap IPs than IDs": {BootstrapIds: []string{"node1"}, BootstrapIps: []string{"1.2.3.4:9651", "1.2.3.5:9651"}},
+		"unpaired IDs when staking":   {StakingTlsEnabled: BoolFlag(true), BootstrapIds: []string{"node1", "node2"}, BootstrapIps: []string{"1.2.3.4:9651"}},
+		"unknown byzantine behavior":  {ByzantineBehavior: "mischief"},
+	}
+	for description, config := range invalidConfigs {
+		assert.Error(t, config.Validate(), "Config with %v should be rejected", description)
+	}
+
+	validConfig := GeckoNodeConfig{
+		HttpPort:          IntFlag(9650),
+		StakingPort:       IntFlag(9651),
+		LogLevel:          LOG_LEVEL_DEBUG,
+		SnowSampleSize:    IntFlag(2),
+		SnowQuorumSize:    IntFlag(2),
+		StakingTlsEnabled: BoolFlag(true),
+		BootstrapIds:      []string{"node1"},
+		BootstrapIps:      []string{"1.2.3.4:9651"},
+		ByzantineBehavior: BYZANTINE_BEHAVIOR_CHIT_SPAMMER,
+	}
+	assert.NoError(t, validConfig.Validate())
+}
+
+func TestMergeRejectsConflictingFlags(t *testing.T) {
+	base := GeckoNodeConfig{
+		SnowSampleSize: IntFlag(2),
+		HttpHost:       StringFlag(""),
+	}
+	merged, err := base.Merge(GeckoNodeConfig{ApiAdminEnabled: BoolFlag(true)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"http-host", "snow-sample-size", "api-admin-enabled"}, merged.GetSetFlagNames())
+
+	_, err = base.Merge(GeckoNodeConfig{HttpHost: StringFlag("127.0.0.1"), SnowSampleSize: IntFlag(3)})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "http-host, snow-sample-size")
+	}
+}
+
+func TestAdditionalNodeConfigCannotSetCoreManagedFlags(t *testing.T) {
+	assert.NoError(t, ValidateAdditionalNodeConfig(GeckoNodeConfig{ApiIpcsEnabled: BoolFlag(true)}))
+	assert.Error(t, ValidateAdditionalNodeConfig(GeckoNodeConfig{SnowQuorumSize: IntFlag(1)}))
+	assert.Error(t, ValidateAdditionalNodeConfig(GeckoNodeConfig{ByzantineBehavior: "mischief"}))
+}
+
+func TestNodeConfigAppendedToStartCommand(t *testing.T) {
+	initializerCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		false,
+		"local",
+		GeckoNodeConfig{ByzantineBehavior: BYZANTINE_BEHAVIOR_CONFLICTING_TXS_VERTEX},
+		[]string{},
+		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		LOG_LEVEL_INFO)
+	actual, err := initializerCore.GetStartCommand(map[string]string{dbDirAnchorFileId: testDbDirAnchorFilepath}, testPublicIp, make([]services.Service, 0))
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Equal(t, "--byzantine-behavior=conflicting-txs-vertex", actual[len(actual)-1])
+
+	conflictingCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		false,
+		"local",
+		GeckoNodeConfig{NetworkId: "12345"},
+		[]string{},
+		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		LOG_LEVEL_INFO)
+	_, err = conflictingCore.GetStartCommand(map[string]string{dbDirAnchorFileId: testDbDirAnchorFilepath}, testPublicIp, make([]services.Service, 0))
+	assert.Error(t, err, "A node config setting a flag the core sets should be rejected")
+}
@@ -0,0 +1,135 @@
+package ava_services
+
+import (
+	"sync"
+)
+
+// The state on the test volume that makes a Gecko node the same node when it's started in a new container
+type GeckoNodeIdentity struct {
+	// Empty if the node isn't staking
+	StakingTlsCertFilepath string
+	StakingTlsKeyFilepath  string
+
+	// The node ID given by the staking cert; empty if the node isn't staking
+	NodeId string
+
+	DbDirpath string
+}
+
+/*
+Records the identity that each Gecko node was started with, and lets a service be started with another node's identity,
+so that a node can be replaced (e.g. with a newer Gecko image) without losing its node ID or database.
+
+Services whose nodes should be recorded must be started through StartService, which starts them one at a time so that
+the initializer cores can tell which service the node they're starting belongs to.
+*/
+type GeckoNodeIdentityRegistry struct {
+	// Held for the whole of a StartService call, so that only one service is started at a time
+	startMutex *sync.Mutex
+
+	// Guards all the fields below
+	mutex *sync.Mutex
+
+	// Public IP -> identity of the node started with that IP
+	startedIdentities map[string]GeckoNodeIdentity
+
+	// ID of the service being started by StartService, or empty if none is
+	startingServiceId string
+
+	// Service ID -> identity that the node being started for that service will take
+	pendingInheritances map[string]GeckoNodeIdentity
+
+	// Service ID -> node ID of the cert written for the node being started for that service
+	pendingNodeIds map[string]string
+}
+
+func NewGeckoNodeIdentityRegistry() *GeckoNodeIdentityRegistry {
+	return &GeckoNodeIdentityRegistry{
+		startMutex:          &sync.Mutex{},
+		mutex:               &sync.Mutex{},
+		startedIdentities:   make(map[string]GeckoNodeIdentity),
+		pendingInheritances: make(map[string]GeckoNodeIdentity),
+		pendingNodeIds:      make(map[string]string),
+	}
+}
+
+/*
+Gets the identity of the node started with the given IP
+
+Args:
+	ipAddr: The public IP of the node
+*/
+func (registry *GeckoNodeIdentityRegistry) GetStartedIdentity(ipAddr string) (GeckoNodeIdentity, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	identity, found := registry.startedIdentities[ipAddr]
+	return identity, found
+}
+
+/*
+Starts a service, recording the identity of its node, and waits for any other service being started to finish first.
+Any inherited identity the start didn't use is dropped when it returns, so it can't be taken by a later service.
+
+Args:
+	serviceId: The ID of the service being started
+	inheritedIdentity: If non-nil, the identity the service's node will take rather than its own cert and database
+	startFunc: Starts the service (e.g. by adding it to the service network) with a core using this registry
+*/
+func (registry *GeckoNodeIdentityRegistry) StartService(serviceId string, inheritedIdentity *GeckoNodeIdentity, startFunc func() error) error {
+	registry.startMutex.Lock()
+	defer registry.startMutex.Unlock()
+
+	registry.mutex.Lock()
+	registry.startingServiceId = serviceId
+	if inheritedIdentity != nil {
+		registry.pendingInheritances[serviceId] = *inheritedIdentity
+	}
+	registry.mutex.Unlock()
+	defer func() {
+		registry.mutex.Lock()
+		defer registry.mutex.Unlock()
+		registry.startingServiceId = ""
+		delete(registry.pendingInheritances, serviceId)
+		delete(registry.pendingNodeIds, serviceId)
+	}()
+
+	return startFunc()
+}
+
+// Consumes the identity that the node being started should take, if any
+func (registry *GeckoNodeIdentityRegistry) popInheritedIdentity() (GeckoNodeIdentity, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	identity, found := registry.pendingInheritances[registry.startingServiceId]
+	if found {
+		delete(registry.pendingInheritances, registry.startingServiceId)
+	}
+	return identity, found
+}
+
+func (registry *GeckoNodeIdentityRegistry) recordStartedIdentity(ipAddr string, identity GeckoNodeIdentity) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.startedIdentities[ipAddr] = identity
+}
+
+// Holds the node ID of the cert written for the node being started; does nothing if no service is being started
+func (registry *GeckoNodeIdentityRegistry) setPendingNodeId(nodeId string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if registry.startingServiceId == "" {
+		return
+	}
+	registry.pendingNodeIds[registry.startingServiceId] = nodeId
+}
+
+// Consumes the node ID of the cert written for the node being started, if any
+func (registry *GeckoNodeIdentityRegistry) popPendingNodeId() (string, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	nodeId, found := registry.pendingNodeIds[registry.startingServiceId]
+	if found {
+		delete(registry.pendingNodeIds, registry.startingServiceId)
+	}
+	return nodeId, found
+}
@@ -9,9 +9,46 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// Aliases of the chains that every Gecko node runs, and which must therefore always be bootstrapped
+	PCHAIN_ALIAS = "P"
+	XCHAIN_ALIAS = "X"
+)
+
 // Implements ServiceAvailabilityCheckerCore
-type GeckoServiceAvailabilityCheckerCore struct{}
+type GeckoServiceAvailabilityCheckerCore struct {
+	bootstrappedChains []string
+}
+
+/*
+Creates a new availability checker core which will only report a Gecko service as up once the service is healthy and
+has finished bootstrapping the P- and X-Chains, as well as any additional chains specified.
 
+Args:
+	additionalBootstrappedChains: IDs or aliases of chains beyond the P- and X-Chains that the Gecko service must have
+		finished bootstrapping before it's considered up
+*/
+func NewGeckoServiceAvailabilityCheckerCore(additionalBootstrappedChains []string) *GeckoServiceAvailabilityCheckerCore {
+	bootstrappedChains := []string{
+		PCHAIN_ALIAS,
+		XCHAIN_ALIAS,
+	}
+	for _, chain := range additionalBootstrappedChains {
+		bootstrappedChains = append(bootstrappedChains, chain)
+	}
+	return &GeckoServiceAvailabilityCheckerCore{
+		bootstrappedChains: bootstrappedChains,
+	}
+}
+
+/*
+Reports the service as up once it's live and has bootstrapped every configured chain.
+
+NOTE: Gecko v0.5.7's health API only has health.getLiveness; there's no separate readiness endpoint to check. Readiness
+is instead inferred from info.isBootstrapped, which is what a readiness check would report on anyway. It hasn't been
+confirmed that the pinned v0.5.7 image serves info.isBootstrapped, so if the node doesn't have the method, the service
+is considered up once it's live, as it was before bootstrapping was checked.
+*/
 func (g GeckoServiceAvailabilityCheckerCore) IsServiceUp(toCheck services.Service, dependencies []services.Service) bool {
 	// NOTE: we don't check the dependencies intentionally, because we don't need to - a Gecko service won't report itself
 	//  as up until its bootstrappers are up
@@ -24,14 +61,28 @@ func (g GeckoServiceAvailabilityCheckerCore) IsServiceUp(toCheck services.Servic
 		logrus.Trace(stacktrace.Propagate(err, "Error occurred getting liveness info"))
 		return false
 	}
-
-	// HACK HACK HACK we need to wait for bootstrapping to finish, and there is not API for this yet (in development)
-	// TODO TODO TODO once isReadiness endpoint is available, use that instead of just waiting
-	if healthInfo.Healthy {
-		time.Sleep(15 * time.Second)
+	if !healthInfo.Healthy {
+		logrus.Tracef("Gecko service at %v is not yet healthy", jsonRpcSocket.GetIpAddr())
+		return false
 	}
 
-	return healthInfo.Healthy
+	// A node that's healthy can still be bootstrapping, and won't be able to serve requests for a chain until it's done
+	for _, chain := range g.bootstrappedChains {
+		isBootstrapped, err := client.InfoApi().IsBootstrapped(chain)
+		if gecko_client.IsRpcError(err, gecko_client.ErrMethodNotFound) {
+			logrus.Debugf("Gecko service at %v doesn't serve info.isBootstrapped, so only its liveness is checked", jsonRpcSocket.GetIpAddr())
+			return true
+		}
+		if err != nil {
+			logrus.Trace(stacktrace.Propagate(err, "Error occurred getting bootstrap status of chain %v", chain))
+			return false
+		}
+		if !isBootstrapped {
+			logrus.Tracef("Gecko service at %v has not yet finished bootstrapping chain %v", jsonRpcSocket.GetIpAddr(), chain)
+			return false
+		}
+	}
+	return true
 }
 
 func (g GeckoServiceAvailabilityCheckerCore) GetTimeout() time.Duration {
@@ -19,6 +19,11 @@ const (
 	stakingTlsCertFileId          = "staking-tls-cert"
 	stakingTlsKeyFileId           = "staking-tls-key"
 
+	// Kurtosis only gives us files on the test volume, not directories, so the node's database goes in a directory
+	//  named after this file, which is unique to the node and survives the node's container being stopped
+	dbDirAnchorFileId = "db-dir-anchor"
+	dbDirSuffix       = "-db"
+
 	testVolumeMountpoint = "/shared"
 )
 
@@ -32,14 +37,36 @@ const (
 )
 
 // ========= Initializer Core ========================
+// The flags that GetStartCommand sets itself, and which therefore can't be set in the core's node config
+var coreManagedFlagNames = map[string]bool{
+	"public-ip":             true,
+	"network-id":            true,
+	"http-port":             true,
+	"http-host":             true,
+	"staking-port":          true,
+	"log-level":             true,
+	"snow-sample-size":      true,
+	"snow-quorum-size":      true,
+	"staking-tls-enabled":   true,
+	"db-dir":                true,
+	"staking-tls-cert-file": true,
+	"staking-tls-key-file":  true,
+	"bootstrap-ids":         true,
+	"bootstrap-ips":         true,
+}
+
 type GeckoServiceInitializerCore struct {
 	snowSampleSize      int
 	snowQuorumSize      int
 	stakingTlsEnabled   bool
-	additionalCLIArgs   map[string]string
+	networkId           string
+	nodeConfig          GeckoNodeConfig
 	bootstrapperNodeIds []string
 	certProvider        cert_providers.GeckoCertProvider
 	logLevel            GeckoLogLevel
+
+	// If non-nil, the identity of every node this core starts is recorded here, and nodes can inherit identities from it
+	identityRegistry *GeckoNodeIdentityRegistry
 }
 
 /*
@@ -49,9 +76,13 @@ Args:
 	snowSampleSize: Sample size for Snow consensus protocol
 	snowQuroumSize: Quorum size for Snow consensus protocol
 	stakingTlsEnabled: Whether this node will use staking & TLS
+	networkId: The name of the network the node will join, which must be one whose genesis Gecko has built in (e.g. "local")
+	nodeConfig: Gecko flags to set in addition to the ones the core sets itself; setting a flag the core already sets is an error
 	bootstrapperNodeIds: The node IDs of the bootstrapper nodes that this node will connect to. While this *seems* unintuitive
 		why this would be required, it's because Gecko doesn't actually use certs. So, to prevent against man-in-the-middle attacks,
-		the user is required to manually specify the node IDs of the nodese it's connecting to.
+		the user is required to manually specify the node IDs of the nodese it's connecting to. With an identity registry,
+		each dependency's ID is looked up by its IP and must be one of these; without one, the dependencies must be given
+		in the same order as these IDs.
 	certProvider: Provides the certs used by the Gecko services generated by this core
 	logLevel: The loglevel that the Gecko node should output at.
 
@@ -62,7 +93,8 @@ func NewGeckoServiceInitializerCore(
 	snowSampleSize int,
 	snowQuorumSize int,
 	stakingTlsEnabled bool,
-	additionalCLIArgs map[string]string,
+	networkId string,
+	nodeConfig GeckoNodeConfig,
 	bootstrapperNodeIds []string,
 	certProvider cert_providers.GeckoCertProvider,
 	logLevel GeckoLogLevel) *GeckoServiceInitializerCore {
@@ -76,13 +108,51 @@ func NewGeckoServiceInitializerCore(
 		snowSampleSize:      snowSampleSize,
 		snowQuorumSize:      snowQuorumSize,
 		stakingTlsEnabled:   stakingTlsEnabled,
-		additionalCLIArgs:   additionalCLIArgs,
+		networkId:           networkId,
+		nodeConfig:          nodeConfig,
 		bootstrapperNodeIds: bootstrapperIdsCopy,
 		certProvider:        certProvider,
 		logLevel:            logLevel,
 	}
 }
 
+/*
+Returns a copy of the core which records the identity of every node it starts in the given registry, and which starts
+a node with another node's identity if the service was started with one through GeckoNodeIdentityRegistry.StartService
+
+Args:
+	registry: The registry to record identities in & inherit identities from
+*/
+func (core GeckoServiceInitializerCore) WithIdentityRegistry(registry *GeckoNodeIdentityRegistry) *GeckoServiceInitializerCore {
+	core.identityRegistry = registry
+	return &core
+}
+
+/*
+Checks that a node config can be given to NewGeckoServiceInitializerCore, i.e. that it's valid and doesn't set any of the
+flags that the core sets itself
+
+Args:
+	nodeConfig: The node config to check
+*/
+func ValidateAdditionalNodeConfig(nodeConfig GeckoNodeConfig) error {
+	conflictingFlagNames := []string{}
+	for _, name := range nodeConfig.GetSetFlagNames() {
+		if coreManagedFlagNames[name] {
+			conflictingFlagNames = append(conflictingFlagNames, name)
+		}
+	}
+	if len(conflictingFlagNames) > 0 {
+		return stacktrace.NewError(
+			"The node config sets the following flags, which are set by the initializer core: %v",
+			strings.Join(conflictingFlagNames, ", "))
+	}
+	if err := nodeConfig.Validate(); err != nil {
+		return stacktrace.Propagate(err, "The node config is invalid")
+	}
+	return nil
+}
+
 func (core GeckoServiceInitializerCore) GetUsedPorts() map[nat.Port]bool {
 	return map[nat.Port]bool{
 		httpPort:    true,
@@ -91,24 +161,39 @@ func (core GeckoServiceInitializerCore) GetUsedPorts() map[nat.Port]bool {
 }
 
 func (core GeckoServiceInitializerCore) GetFilesToMount() map[string]bool {
+	result := map[string]bool{
+		dbDirAnchorFileId: true,
+	}
 	if core.stakingTlsEnabled {
-		return map[string]bool{
-			stakingTlsCertFileId: true,
-			stakingTlsKeyFileId:  true,
-		}
+		result[stakingTlsCertFileId] = true
+		result[stakingTlsKeyFileId] = true
 	}
-	return make(map[string]bool)
+	return result
 }
 
 func (core GeckoServiceInitializerCore) InitializeMountedFiles(osFiles map[string]*os.File, dependencies []services.Service) (err error) {
-	certFilePointer := osFiles[stakingTlsCertFileId]
-	keyFilePointer := osFiles[stakingTlsKeyFileId]
-	certPEM, keyPEM, err := core.certProvider.GetCertAndKey()
-	if err != nil {
-		return stacktrace.Propagate(err, "Could not get cert & key when initializing service")
+	if _, err := osFiles[dbDirAnchorFileId].WriteString("This node's database is in the directory of the same name ending in " + dbDirSuffix); err != nil {
+		return stacktrace.Propagate(err, "Could not write database directory anchor file when initializing service")
+	}
+	if core.stakingTlsEnabled {
+		certFilePointer := osFiles[stakingTlsCertFileId]
+		keyFilePointer := osFiles[stakingTlsKeyFileId]
+		certPEM, keyPEM, err := core.certProvider.GetCertAndKey()
+		if err != nil {
+			return stacktrace.Propagate(err, "Could not get cert & key when initializing service")
+		}
+		certFilePointer.Write(certPEM.Bytes())
+		keyFilePointer.Write(keyPEM.Bytes())
+
+		// The node's IP isn't known until the start command is built, so its node ID is held until then
+		if core.identityRegistry != nil {
+			nodeId, err := cert_providers.ComputeNodeId(certPEM)
+			if err != nil {
+				return stacktrace.Propagate(err, "Could not compute the node ID of the cert when initializing service")
+			}
+			core.identityRegistry.setPendingNodeId(nodeId)
+		}
 	}
-	certFilePointer.Write(certPEM.Bytes())
-	keyFilePointer.Write(keyPEM.Bytes())
 	return nil
 }
 
@@ -123,37 +208,48 @@ func (core GeckoServiceInitializerCore) GetStartCommand(mountedFileFilepaths map
 		)
 	}
 
-	publicIpFlag := fmt.Sprintf("--public-ip=%s", publicIpAddr.String())
-	commandList := []string{
-		"/gecko/build/ava",
-		publicIpFlag,
-		"--network-id=local",
-		fmt.Sprintf("--http-port=%d", httpPort.Int()),
-		"--http-host=", // Leave empty to make API openly accessible
-		fmt.Sprintf("--staking-port=%d", stakingPort.Int()),
-		fmt.Sprintf("--log-level=%s", core.logLevel),
-		fmt.Sprintf("--snow-sample-size=%d", core.snowSampleSize),
-		fmt.Sprintf("--snow-quorum-size=%d", core.snowQuorumSize),
-		fmt.Sprintf("--staking-tls-enabled=%v", core.stakingTlsEnabled),
+	managedConfig := GeckoNodeConfig{
+		PublicIp:          publicIpAddr.String(),
+		NetworkId:         core.networkId,
+		HttpPort:          IntFlag(httpPort.Int()),
+		HttpHost:          StringFlag(""), // Leave empty to make API openly accessible
+		StakingPort:       IntFlag(stakingPort.Int()),
+		LogLevel:          core.logLevel,
+		SnowSampleSize:    IntFlag(core.snowSampleSize),
+		SnowQuorumSize:    IntFlag(core.snowQuorumSize),
+		StakingTlsEnabled: BoolFlag(core.stakingTlsEnabled),
 	}
 
-	if core.stakingTlsEnabled {
-		certFilepath, found := mountedFileFilepaths[stakingTlsCertFileId]
-		if !found {
-			return nil, stacktrace.NewError("Could not find file key '%v' in the mounted filepaths map; this is likely a code bug", stakingTlsCertFileId)
+	identity, err := core.getOwnIdentity(mountedFileFilepaths)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not get the identity of the node")
+	}
+	if core.identityRegistry != nil {
+		if nodeId, found := core.identityRegistry.popPendingNodeId(); found {
+			identity.NodeId = nodeId
 		}
-		keyFilepath, found := mountedFileFilepaths[stakingTlsKeyFileId]
-		if !found {
-			return nil, stacktrace.NewError("Could not find file key '%v' in the mounted filepaths map; this is likely a code bug", stakingTlsKeyFileId)
+		if inheritedIdentity, found := core.identityRegistry.popInheritedIdentity(); found {
+			logrus.Debugf("Node at %v is inheriting identity %+v", publicIpAddr.String(), inheritedIdentity)
+			identity = inheritedIdentity
 		}
-		commandList = append(commandList, fmt.Sprintf("--staking-tls-cert-file=%s", certFilepath))
-		commandList = append(commandList, fmt.Sprintf("--staking-tls-key-file=%s", keyFilepath))
+		core.identityRegistry.recordStartedIdentity(publicIpAddr.String(), identity)
+	}
+	managedConfig.DbDir = identity.DbDirpath
+
+	if core.stakingTlsEnabled {
+		managedConfig.StakingTlsCertFile = identity.StakingTlsCertFilepath
+		managedConfig.StakingTlsKeyFile = identity.StakingTlsKeyFilepath
 
 		// NOTE: This seems weird, BUT there's a reason for it: Gecko doesn't use certs, and instead relies on
 		//  the user explicitly passing in the node ID of the bootstrapper it wants. This prevents man-in-the-middle
 		//  attacks, just like using a cert would. Us hardcoding this bootstrapper ID here is the equivalent
 		//  of a user knowing the node ID in advance, which provides the same level of protection.
-		commandList = append(commandList, "--bootstrap-ids="+strings.Join(core.bootstrapperNodeIds, ","))
+		// Gecko pairs bootstrap IDs with bootstrap IPs by position, so the IDs must be those of the dependencies, in order
+		bootstrapIds, err := core.getDependencyNodeIds(dependencies)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Could not get the node IDs of the node's bootstrappers")
+		}
+		managedConfig.BootstrapIds = bootstrapIds
 	}
 
 	if len(dependencies) > 0 {
@@ -167,20 +263,81 @@ func (core GeckoServiceInitializerCore) GetStartCommand(mountedFileFilepaths map
 			socket := service.GetStakingSocket()
 			socketStrs = append(socketStrs, fmt.Sprintf("%s:%d", socket.GetIpAddr(), socket.GetPort().Int()))
 		}
-		joinedSockets := strings.Join(socketStrs, ",")
-		commandList = append(commandList, "--bootstrap-ips="+joinedSockets)
+		managedConfig.BootstrapIps = socketStrs
 	}
 
-	// Append additional CLI arguments
-	// These are added as is with no additional checking
-	for param, argument := range core.additionalCLIArgs {
-		commandList = append(commandList, fmt.Sprintf("--%s=%s", param, argument))
+	fullConfig, err := managedConfig.Merge(core.nodeConfig)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "The node config sets flags that the initializer core already sets")
+	}
+	if err := fullConfig.Validate(); err != nil {
+		return nil, stacktrace.Propagate(err, "The Gecko node config is invalid")
 	}
 
+	commandList := append([]string{"/gecko/build/ava"}, fullConfig.ToCliArgs()...)
 	logrus.Debugf("Command list: %+v", commandList)
 	return commandList, nil
 }
 
+/*
+Gets the node IDs of the given dependencies, in the same order as the dependencies. If the core has an identity registry,
+each ID is the one recorded for the dependency's IP; otherwise, the dependencies are assumed to be the first of the
+configured bootstrappers, in order.
+*/
+func (core GeckoServiceInitializerCore) getDependencyNodeIds(dependencies []services.Service) ([]string, error) {
+	if core.identityRegistry == nil {
+		// There being no more dependencies than bootstrapper IDs was checked when building the start command
+		return append([]string{}, core.bootstrapperNodeIds[:len(dependencies)]...), nil
+	}
+
+	configuredNodeIds := make(map[string]bool)
+	for _, nodeId := range core.bootstrapperNodeIds {
+		configuredNodeIds[nodeId] = true
+	}
+	nodeIds := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		socket := dependency.(AvaService).GetStakingSocket()
+		dependencyIp := socket.GetIpAddr()
+		identity, found := core.identityRegistry.GetStartedIdentity(dependencyIp)
+		if !found || identity.NodeId == "" {
+			return nil, stacktrace.NewError("No node ID was recorded for dependency with IP %v; this is likely a code bug", dependencyIp)
+		}
+		if !configuredNodeIds[identity.NodeId] {
+			return nil, stacktrace.NewError(
+				"Dependency with IP %v has node ID %v, which isn't one of the configured boot node IDs %v",
+				dependencyIp,
+				identity.NodeId,
+				core.bootstrapperNodeIds)
+		}
+		nodeIds = append(nodeIds, identity.NodeId)
+	}
+	return nodeIds, nil
+}
+
+// Gets the identity made up of the files mounted for the node, which it has unless it inherits another node's identity
+func (core GeckoServiceInitializerCore) getOwnIdentity(mountedFileFilepaths map[string]string) (GeckoNodeIdentity, error) {
+	dbDirAnchorFilepath, found := mountedFileFilepaths[dbDirAnchorFileId]
+	if !found {
+		return GeckoNodeIdentity{}, stacktrace.NewError("Could not find file key '%v' in the mounted filepaths map; this is likely a code bug", dbDirAnchorFileId)
+	}
+	identity := GeckoNodeIdentity{
+		DbDirpath: dbDirAnchorFilepath + dbDirSuffix,
+	}
+	if core.stakingTlsEnabled {
+		certFilepath, found := mountedFileFilepaths[stakingTlsCertFileId]
+		if !found {
+			return GeckoNodeIdentity{}, stacktrace.NewError("Could not find file key '%v' in the mounted filepaths map; this is likely a code bug", stakingTlsCertFileId)
+		}
+		keyFilepath, found := mountedFileFilepaths[stakingTlsKeyFileId]
+		if !found {
+			return GeckoNodeIdentity{}, stacktrace.NewError("Could not find file key '%v' in the mounted filepaths map; this is likely a code bug", stakingTlsKeyFileId)
+		}
+		identity.StakingTlsCertFilepath = certFilepath
+		identity.StakingTlsKeyFilepath = keyFilepath
+	}
+	return identity, nil
+}
+
 func (core GeckoServiceInitializerCore) GetServiceFromIp(ipAddr string) services.Service {
 	return GeckoService{
 		ipAddr:      ipAddr,
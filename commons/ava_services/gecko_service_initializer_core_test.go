@@ -2,8 +2,12 @@ package ava_services
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services/cert_providers"
@@ -13,12 +17,15 @@ import (
 
 var testPublicIp = net.ParseIP("172.17.0.2")
 
+const testDbDirAnchorFilepath = "/shared/db-dir-anchor"
+
 func TestNoDepsStartCommand(t *testing.T) {
 	initializerCore := NewGeckoServiceInitializerCore(
 		1,
 		1,
 		false,
-		make(map[string]string),
+		"local",
+		GeckoNodeConfig{},
 		[]string{},
 		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
 		LOG_LEVEL_INFO)
@@ -34,8 +41,9 @@ func TestNoDepsStartCommand(t *testing.T) {
 		"--snow-sample-size=1",
 		"--snow-quorum-size=1",
 		"--staking-tls-enabled=false",
+		"--db-dir=/shared/db-dir-anchor-db",
 	}
-	actual, err := initializerCore.GetStartCommand(make(map[string]string), testPublicIp, make([]services.Service, 0))
+	actual, err := initializerCore.GetStartCommand(map[string]string{dbDirAnchorFileId: testDbDirAnchorFilepath}, testPublicIp, make([]services.Service, 0))
 	assert.NoError(t, err, "An error occurred getting the start command")
 	assert.Equal(t, expected, actual)
 }
@@ -51,7 +59,8 @@ func TestWithDepsStartCommand(t *testing.T) {
 		1,
 		1,
 		false,
-		make(map[string]string),
+		"local",
+		GeckoNodeConfig{},
 		bootstrapperNodeIds,
 		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
 		LOG_LEVEL_INFO)
@@ -67,6 +76,7 @@ func TestWithDepsStartCommand(t *testing.T) {
 		"--snow-sample-size=1",
 		"--snow-quorum-size=1",
 		"--staking-tls-enabled=false",
+		"--db-dir=/shared/db-dir-anchor-db",
 		fmt.Sprintf("--bootstrap-ips=%v:9651", testDependencyIp),
 	}
 
@@ -78,7 +88,217 @@ func TestWithDepsStartCommand(t *testing.T) {
 	testDependencySlice := []services.Service{
 		testDependency,
 	}
-	actual, err := initializerCore.GetStartCommand(make(map[string]string), testPublicIp, testDependencySlice)
+	actual, err := initializerCore.GetStartCommand(map[string]string{dbDirAnchorFileId: testDbDirAnchorFilepath}, testPublicIp, testDependencySlice)
 	assert.NoError(t, err, "An error occurred getting the start command")
 	assert.Equal(t, expected, actual)
 }
+
+func TestInheritedIdentityStartCommand(t *testing.T) {
+	registry := NewGeckoNodeIdentityRegistry()
+	oldCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		true,
+		"local",
+		GeckoNodeConfig{},
+		[]string{},
+		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		LOG_LEVEL_INFO).WithIdentityRegistry(registry)
+	newCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		true,
+		"local",
+		GeckoNodeConfig{},
+		[]string{},
+		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		LOG_LEVEL_INFO).WithIdentityRegistry(registry)
+
+	oldMountedFileFilepaths := map[string]string{
+		dbDirAnchorFileId:    "/shared/old/db-dir-anchor",
+		stakingTlsCertFileId: "/shared/old/staking.crt",
+		stakingTlsKeyFileId:  "/shared/old/staking.key",
+	}
+	err := registry.StartService("old-service", nil, func() error {
+		_, err := oldCore.GetStartCommand(oldMountedFileFilepaths, testPublicIp, make([]services.Service, 0))
+		return err
+	})
+	assert.NoError(t, err, "An error occurred getting the start command")
+	oldIdentity, found := registry.GetStartedIdentity(testPublicIp.String())
+	assert.True(t, found, "Started node's identity should have been recorded")
+	assert.Equal(t, "/shared/old/db-dir-anchor-db", oldIdentity.DbDirpath)
+
+	newMountedFileFilepaths := map[string]string{
+		dbDirAnchorFileId:    "/shared/new/db-dir-anchor",
+		stakingTlsCertFileId: "/shared/new/staking.crt",
+		stakingTlsKeyFileId:  "/shared/new/staking.key",
+	}
+	newPublicIp := net.ParseIP("172.17.0.3")
+	var inheritingCommand []string
+	err = registry.StartService("old-service", &oldIdentity, func() error {
+		var err error
+		inheritingCommand, err = newCore.GetStartCommand(newMountedFileFilepaths, newPublicIp, make([]services.Service, 0))
+		return err
+	})
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Contains(t, inheritingCommand, "--db-dir=/shared/old/db-dir-anchor-db")
+	assert.Contains(t, inheritingCommand, "--staking-tls-cert-file=/shared/old/staking.crt")
+	assert.Contains(t, inheritingCommand, "--staking-tls-key-file=/shared/old/staking.key")
+
+	// The inheritance only applies to the service it was given for, even if another service uses the same core
+	var otherCommand []string
+	err = registry.StartService("other-service", nil, func() error {
+		var err error
+		otherCommand, err = newCore.GetStartCommand(newMountedFileFilepaths, newPublicIp, make([]services.Service, 0))
+		return err
+	})
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Contains(t, otherCommand, "--db-dir=/shared/new/db-dir-anchor-db")
+	assert.Contains(t, otherCommand, "--staking-tls-cert-file=/shared/new/staking.crt")
+}
+
+func TestUnusedInheritedIdentityIsDropped(t *testing.T) {
+	registry := NewGeckoNodeIdentityRegistry()
+	core := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		true,
+		"local",
+		GeckoNodeConfig{},
+		[]string{},
+		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		LOG_LEVEL_INFO).WithIdentityRegistry(registry)
+	inheritedIdentity := GeckoNodeIdentity{
+		StakingTlsCertFilepath: "/shared/old/staking.crt",
+		StakingTlsKeyFilepath:  "/shared/old/staking.key",
+		DbDirpath:              "/shared/old/db-dir-anchor-db",
+	}
+	err := registry.StartService("upgraded-service", &inheritedIdentity, func() error {
+		return errors.New("Test error adding the service")
+	})
+	assert.Error(t, err)
+
+	// A later start of the same service mustn't take the identity left by the failed one
+	mountedFileFilepaths := map[string]string{
+		dbDirAnchorFileId:    "/shared/new/db-dir-anchor",
+		stakingTlsCertFileId: "/shared/new/staking.crt",
+		stakingTlsKeyFileId:  "/shared/new/staking.key",
+	}
+	var command []string
+	err = registry.StartService("upgraded-service", nil, func() error {
+		var err error
+		command, err = core.GetStartCommand(mountedFileFilepaths, testPublicIp, make([]services.Service, 0))
+		return err
+	})
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Contains(t, command, "--db-dir=/shared/new/db-dir-anchor-db")
+}
+
+func TestFewerDependenciesThanBootstrappersWithoutRegistry(t *testing.T) {
+	initializerCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		true,
+		"local",
+		GeckoNodeConfig{},
+		[]string{"node1", "node2"},
+		cert_providers.NewStaticGeckoCertProvider(bytes.Buffer{}, bytes.Buffer{}),
+		LOG_LEVEL_INFO)
+	mountedFileFilepaths := map[string]string{
+		dbDirAnchorFileId:    testDbDirAnchorFilepath,
+		stakingTlsCertFileId: "/shared/staking.crt",
+		stakingTlsKeyFileId:  "/shared/staking.key",
+	}
+	dependencies := []services.Service{
+		initializerCore.GetServiceFromIp("172.17.0.2"),
+	}
+	command, err := initializerCore.GetStartCommand(mountedFileFilepaths, testPublicIp, dependencies)
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Contains(t, command, "--bootstrap-ids=node1")
+	assert.Contains(t, command, "--bootstrap-ips=172.17.0.2:9651")
+}
+
+func TestBootstrapIdsFollowDependencyOrder(t *testing.T) {
+	tempDirpath, err := ioutil.TempDir("", "initializer-core")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDirpath)
+
+	registry := NewGeckoNodeIdentityRegistry()
+	bootNodeIps := []net.IP{net.ParseIP("172.17.0.2"), net.ParseIP("172.17.0.3")}
+	bootNodeIds := []string{}
+	for i, bootNodeIp := range bootNodeIps {
+		certPem, keyPem, err := cert_providers.GenerateSeededCertAndKey(42, i)
+		assert.NoError(t, err)
+		nodeId, err := cert_providers.ComputeNodeId(*certPem)
+		assert.NoError(t, err)
+		bootNodeIds = append(bootNodeIds, nodeId)
+
+		bootCore := NewGeckoServiceInitializerCore(
+			1,
+			1,
+			true,
+			"local",
+			GeckoNodeConfig{},
+			[]string{},
+			cert_providers.NewStaticGeckoCertProvider(*keyPem, *certPem),
+			LOG_LEVEL_INFO).WithIdentityRegistry(registry)
+		err = registry.StartService(fmt.Sprintf("boot-node-%v", i), nil, func() error {
+			mountedFileFilepaths := initializeMountedFiles(t, *bootCore, filepath.Join(tempDirpath, bootNodeIp.String()))
+			_, err := bootCore.GetStartCommand(mountedFileFilepaths, bootNodeIp, make([]services.Service, 0))
+			return err
+		})
+		assert.NoError(t, err, "An error occurred getting the start command of boot node %v", i)
+	}
+
+	userCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		true,
+		"local",
+		GeckoNodeConfig{},
+		bootNodeIds,
+		cert_providers.NewRandomGeckoCertProvider(false),
+		LOG_LEVEL_INFO).WithIdentityRegistry(registry)
+	mountedFileFilepaths := initializeMountedFiles(t, *userCore, filepath.Join(tempDirpath, "user"))
+
+	// Dependencies needn't be in the order the boot node IDs were configured in
+	dependencies := []services.Service{
+		userCore.GetServiceFromIp(bootNodeIps[1].String()),
+		userCore.GetServiceFromIp(bootNodeIps[0].String()),
+	}
+	command, err := userCore.GetStartCommand(mountedFileFilepaths, net.ParseIP("172.17.0.4"), dependencies)
+	assert.NoError(t, err, "An error occurred getting the start command")
+	assert.Contains(t, command, fmt.Sprintf("--bootstrap-ids=%v,%v", bootNodeIds[1], bootNodeIds[0]))
+	assert.Contains(t, command, "--bootstrap-ips=172.17.0.3:9651,172.17.0.2:9651")
+
+	// A dependency whose node ID wasn't configured can't be bootstrapped from
+	onlyFirstBootNodeCore := NewGeckoServiceInitializerCore(
+		1,
+		1,
+		true,
+		"local",
+		GeckoNodeConfig{},
+		bootNodeIds[0:1],
+		cert_providers.NewRandomGeckoCertProvider(false),
+		LOG_LEVEL_INFO).WithIdentityRegistry(registry)
+	mountedFileFilepaths = initializeMountedFiles(t, *onlyFirstBootNodeCore, filepath.Join(tempDirpath, "other-user"))
+	_, err = onlyFirstBootNodeCore.GetStartCommand(mountedFileFilepaths, net.ParseIP("172.17.0.5"), dependencies[0:1])
+	assert.Error(t, err)
+}
+
+// Creates the files the core asks for in the given directory and initializes them, returning the file key -> filepath map
+func initializeMountedFiles(t *testing.T, core GeckoServiceInitializerCore, dirpath string) map[string]string {
+	assert.NoError(t, os.MkdirAll(dirpath, 0755))
+	osFiles := make(map[string]*os.File)
+	mountedFileFilepaths := make(map[string]string)
+	for fileId := range core.GetFilesToMount() {
+		fileFilepath := filepath.Join(dirpath, fileId)
+		file, err := os.Create(fileFilepath)
+		assert.NoError(t, err)
+		defer file.Close()
+		osFiles[fileId] = file
+		mountedFileFilepaths[fileId] = fileFilepath
+	}
+	assert.NoError(t, core.InitializeMountedFiles(osFiles, make([]services.Service, 0)))
+	return mountedFileFilepaths
+}
@@ -1,44 +1,203 @@
 package ava_testsuite
 
 import (
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/conflicting_txs_vertex_test"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/duplicate_node_id_test"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/fully_connected_test"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/keystore_migration_test"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/node_restart_test"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/partition_conflicting_txs_test"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rolling_upgrade_test"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_test"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/unrequested_chit_spammer_test"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/verifier"
 	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
 )
 
+// The variables which the images in network specs can reference (e.g. "${GECKO_IMAGE}"), each set to one of the suite's images
+const (
+	geckoImageVariable     = "GECKO_IMAGE"
+	byzantineImageVariable = "BYZANTINE_IMAGE"
+	upgradeImageVariable   = "UPGRADE_IMAGE"
+)
+
 type AvaTestSuite struct {
 	ByzantineImageName string
 	NormalImageName    string
+
+	// If non-empty, the rolling upgrade test will upgrade nodes running NormalImageName to this image, which must report a
+	//  different node version
+	UpgradeImageName string
+
+	// The topology of each test's network, keyed by test name; a test without a network spec can't be run
+	NetworkSpecs ava_networks.NetworkSpecs
+
+	// If non-empty, the JSON RPC requests made by each test will be recorded to a file in this directory
+	RpcRecordingDirpath string
+
+	// If non-zero, the certs of each test's nodes will be generated from this seed rather than randomly
+	CertSeed int64
 }
 
+// Gets the tests which can be run with the suite's configuration; see GetTestCatalog for every test in the suite
 func (a AvaTestSuite) GetTests() map[string]testsuite.Test {
 	result := make(map[string]testsuite.Test)
-
-	if a.ByzantineImageName != "" {
-		result["stakingNetworkChitSpammerTest"] = unrequested_chit_spammer_test.StakingNetworkUnrequestedChitSpammerTest{
-			ByzantineImageName: a.ByzantineImageName,
-			NormalImageName:    a.NormalImageName,
+	for name, definition := range getTestDefinitions() {
+		if a.getUnavailableReason(name, definition.metadata) != "" {
+			continue
+		}
+		var test testsuite.Test = definition.create(a, a.NetworkSpecs[name])
+		if a.CertSeed != 0 {
+			test = certSeededTest{Test: test, certSeed: a.CertSeed}
 		}
-		result["conflictingTxsVertexTest"] = conflicting_txs_vertex_test.StakingNetworkConflictingTxsVertexTest{
-			ByzantineImageName: a.ByzantineImageName,
-			NormalImageName:    a.NormalImageName,
+		if a.RpcRecordingDirpath != "" {
+			test = newRpcRecordingTest(test, a.RpcRecordingDirpath, name)
 		}
+		result[name] = executionContextBoundTest{Test: test}
 	}
-	result["stakingNetworkFullyConnectedTest"] = fully_connected_test.StakingNetworkFullyConnectedTest{
-		ImageName: a.NormalImageName,
-		Verifier:  verifier.NetworkStateVerifier{},
+	return result
+}
+
+// Gets every test in the suite, keyed by test name
+func getTestDefinitions() map[string]avaTestDefinition {
+	return map[string]avaTestDefinition{
+		"stakingNetworkChitSpammerTest": {
+			metadata: TestMetadata{
+				Description:    "Byzantine nodes spam unrequested chits, and the network must keep accepting transactions",
+				Tags:           []string{TAG_BYZANTINE, TAG_STAKING},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL, REQUIRED_IMAGE_BYZANTINE},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return unrequested_chit_spammer_test.StakingNetworkUnrequestedChitSpammerTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+				}
+			},
+		},
+		"conflictingTxsVertexTest": {
+			metadata: TestMetadata{
+				Description:    "A byzantine node issues conflicting transactions in one vertex, which virtuous nodes must drop",
+				Tags:           []string{TAG_BYZANTINE, TAG_STAKING},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL, REQUIRED_IMAGE_BYZANTINE},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return conflicting_txs_vertex_test.StakingNetworkConflictingTxsVertexTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+				}
+			},
+		},
+		"stakingNetworkRollingUpgradeTest": {
+			metadata: TestMetadata{
+				Description:    "Nodes are upgraded to the upgrade image one at a time, and each must report the new version while the network stays connected and consistent",
+				Tags:           []string{TAG_STAKING, TAG_UPGRADE, TAG_SLOW},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL, REQUIRED_IMAGE_UPGRADE},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return rolling_upgrade_test.StakingNetworkRollingUpgradeTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+					NewImageName:   a.UpgradeImageName,
+					Verifier:       verifier.NetworkStateVerifier{},
+				}
+			},
+		},
+		"stakingNetworkFullyConnectedTest": {
+			metadata: TestMetadata{
+				Description:    "A new validator joins, and every node must be connected to every validator once it's gossiped",
+				Tags:           []string{TAG_STAKING, TAG_SMOKE, TAG_SLOW},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return fully_connected_test.StakingNetworkFullyConnectedTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+					Verifier:       verifier.NetworkStateVerifier{},
+				}
+			},
+		},
+		"stakingNetworkDuplicateNodeIdTest": {
+			metadata: TestMetadata{
+				Description:    "Nodes sharing a node ID join the network, which must stay connected to the original node",
+				Tags:           []string{TAG_STAKING},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return duplicate_node_id_test.DuplicateNodeIdTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+					Verifier:       verifier.NetworkStateVerifier{},
+				}
+			},
+		},
+		"stakingNetworkRpcWorkflowTest": {
+			metadata: TestMetadata{
+				Description:    "Funds, stakes, and delegates through the JSON RPC APIs, checking balances along the way",
+				Tags:           []string{TAG_STAKING, TAG_SMOKE},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return rpc_workflow_test.StakingNetworkRpcWorkflowTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+				}
+			},
+		},
+		"stakingNetworkKeystoreMigrationTest": {
+			metadata: TestMetadata{
+				Description:    "A user is exported from one node's keystore and imported into another's, which must be able to spend their funds",
+				Tags:           []string{TAG_STAKING},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return keystore_migration_test.StakingNetworkKeystoreMigrationTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+				}
+			},
+		},
+		"stakingNetworkPartitionConflictingTxsTest": {
+			metadata: TestMetadata{
+				Description:    "Conflicting transactions are issued on either side of a network partition, which must never both be accepted",
+				Tags:           []string{TAG_STAKING},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return partition_conflicting_txs_test.StakingNetworkPartitionConflictingTxsTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+				}
+			},
+		},
+		"stakingNetworkNodeRestartTest": {
+			metadata: TestMetadata{
+				Description:    "A validator is stopped, killed, and paused, and each time must keep its node ID, state, and validator status and rejoin its peers",
+				Tags:           []string{TAG_STAKING},
+				RequiredImages: []RequiredImage{REQUIRED_IMAGE_NORMAL},
+			},
+			create: func(a AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test {
+				return node_restart_test.StakingNetworkNodeRestartTest{
+					NetworkSpec:    networkSpec,
+					ImageVariables: a.getImageVariables(),
+				}
+			},
+		},
 	}
-	result["stakingNetworkDuplicateNodeIdTest"] = duplicate_node_id_test.DuplicateNodeIdTest{
-		ImageName: a.NormalImageName,
-		Verifier:  verifier.NetworkStateVerifier{},
+}
+
+// Gets the values of the variables which network spec images can reference, leaving out the suite's unset images
+func (a AvaTestSuite) getImageVariables() map[string]string {
+	suiteImages := map[string]string{
+		geckoImageVariable:     a.NormalImageName,
+		byzantineImageVariable: a.ByzantineImageName,
+		upgradeImageVariable:   a.UpgradeImageName,
 	}
-	result["stakingNetworkRpcWorkflowTest"] = rpc_workflow_test.StakingNetworkRpcWorkflowTest{
-		ImageName: a.NormalImageName,
+	result := make(map[string]string)
+	for variable, imageName := range suiteImages {
+		if imageName != "" {
+			result[variable] = imageName
+		}
 	}
-
 	return result
 }
@@ -0,0 +1,31 @@
+package ava_testsuite
+
+import (
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+)
+
+/*
+Wraps a test so that the certs of the nodes in its network are generated from a seed, so that a failing run can be
+reproduced with the same node IDs
+*/
+type certSeededTest struct {
+	testsuite.Test
+
+	certSeed int64
+}
+
+func (test certSeededTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	loader, err := test.Test.GetNetworkLoader()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not get network loader of wrapped test")
+	}
+	castedLoader, ok := loader.(*ava_networks.TestGeckoNetworkLoader)
+	if !ok {
+		return nil, stacktrace.NewError("Cert seeding is only supported for tests using a %T", castedLoader)
+	}
+	seededLoader := castedLoader.WithCertSeed(test.certSeed)
+	return &seededLoader, nil
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/ava-labs/gecko/snow/choices"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
-	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
 	"github.com/kurtosis-tech/kurtosis/commons/networks"
 	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
 	"github.com/palantir/stacktrace"
@@ -15,25 +15,25 @@ import (
 )
 
 const (
-	normalNodeConfigId          networks.ConfigurationID = "normal-config"
-	byzantineConfigId           networks.ConfigurationID = "byzantine-config"
-	byzantineUsername                                    = "byzantine_gecko"
-	byzantinePassword                                    = "byzant1n3!"
-	byzantineBehavior                                    = "byzantine-behavior"
-	conflictingTxVertexBehavior                          = "conflicting-txs-vertex"
-	stakerUsername                                       = "staker_gecko"
-	stakerPassword                                       = "test34test!23"
-	byzantineNodeServiceId                               = "byzantine-node"
-	normalNodeServiceId                                  = "virtuous-node"
-	seedAmount                                           = int64(50000000000000)
-	stakeAmount                                          = int64(30000000000000)
+	byzantineUsername      = "byzantine_gecko"
+	byzantinePassword      = "byzant1n3!"
+	stakerUsername         = "staker_gecko"
+	stakerPassword         = "test34test!23"
+	byzantineNodeServiceId = "byzantine-node"
+	normalNodeServiceId    = "virtuous-node"
+	seedAmount             = int64(50000000000000)
+	stakeAmount            = int64(30000000000000)
+
+	// Must leave enough of the execution timeout for the checks that happen after the virtuous transaction is accepted
+	virtuousTxAcceptanceTimeoutRatio = 0.75
 )
 
 // ================ Byzantine Test - Conflicting Transactions in a Vertex Test ===================================
 // StakingNetworkConflictingTxsVertexTest implements the Test interface
 type StakingNetworkConflictingTxsVertexTest struct {
-	ByzantineImageName string
-	NormalImageName    string
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
 }
 
 // Issue conflicting transactions to the byzantine node to be issued into a vertex
@@ -45,6 +45,7 @@ type StakingNetworkConflictingTxsVertexTest struct {
 // in processing.
 func (test StakingNetworkConflictingTxsVertexTest) Run(network networks.Network, context testsuite.TestContext) {
 	castedNetwork := network.(ava_networks.TestGeckoNetwork)
+	virtuousTxAcceptanceTimeout := time.Duration(virtuousTxAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
 
 	byzantineClient, err := castedNetwork.GetGeckoClient(byzantineNodeServiceId)
 	if err != nil {
@@ -139,19 +140,11 @@ func (test StakingNetworkConflictingTxsVertexTest) Run(network networks.Network,
 		context.Fatal(stacktrace.Propagate(err, "Failed to issue virtuous transaction spending created asset after issuing byzantine vertex"))
 	}
 
-	for {
-		status, err := virtuousXChainAPI.GetTxStatus(virtuousSpendTxId)
-		if err != nil {
-			context.Fatal(stacktrace.Propagate(err, "Failed to get virtuous transactions status from virtuous node"))
-		}
-		if status == choices.Accepted.String() {
-			logrus.Infof("Accepted virtuous transaction with ID: %s", virtuousSpendTxId)
-			break
-		} else {
-			logrus.Infof("Waiting for transaction with ID: %s to be accepted", virtuousSpendTxId)
-			time.Sleep(2 * time.Second)
-		}
+	err = rpc_workflow_runner.WaitForXchainTransactionAcceptance(virtuousClient, virtuousSpendTxId, virtuousTxAcceptanceTimeout)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Virtuous transaction was not accepted by the virtuous node"))
 	}
+	logrus.Infof("Accepted virtuous transaction with ID: %s", virtuousSpendTxId)
 
 	// Once the virtuous transaction was accepted, check to see if the non-conflicting transaction
 	// in an illegal vertex was accepted
@@ -168,12 +161,7 @@ func (test StakingNetworkConflictingTxsVertexTest) Run(network networks.Network,
 }
 
 func (test StakingNetworkConflictingTxsVertexTest) GetNetworkLoader() (networks.NetworkLoader, error) {
-	// Provision a byzantine and normal node
-	desiredServices := map[networks.ServiceID]networks.ConfigurationID{}
-	desiredServices[byzantineNodeServiceId] = byzantineConfigId
-	desiredServices[normalNodeServiceId] = normalNodeConfigId
-
-	return getByzantineNetworkLoader(desiredServices, test.ByzantineImageName, test.NormalImageName)
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
 }
 
 func (test StakingNetworkConflictingTxsVertexTest) GetExecutionTimeout() time.Duration {
@@ -183,42 +171,3 @@ func (test StakingNetworkConflictingTxsVertexTest) GetExecutionTimeout() time.Du
 func (test StakingNetworkConflictingTxsVertexTest) GetSetupBuffer() time.Duration {
 	return 2 * time.Minute
 }
-
-// =============== Helper functions =============================
-
-/*
-Args:
-	desiredServices: Mapping of service_id -> configuration_id for all services *in addition to the boot nodes* that the user wants
-*/
-func getByzantineNetworkLoader(desiredServices map[networks.ServiceID]networks.ConfigurationID, byzantineImageName string, normalImageName string) (networks.NetworkLoader, error) {
-	serviceConfigs := map[networks.ConfigurationID]ava_networks.TestGeckoNetworkServiceConfig{
-		normalNodeConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(
-			true,
-			ava_services.LOG_LEVEL_DEBUG,
-			normalImageName,
-			2,
-			2,
-			make(map[string]string),
-		),
-		byzantineConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(
-			true,
-			ava_services.LOG_LEVEL_DEBUG,
-			byzantineImageName,
-			2,
-			2,
-			map[string]string{byzantineBehavior: conflictingTxVertexBehavior},
-		),
-	}
-	logrus.Debugf("Byzantine Image Name: %s", byzantineImageName)
-	logrus.Debugf("Normal Image Name: %s", normalImageName)
-
-	return ava_networks.NewTestGeckoNetworkLoader(
-		true,
-		normalImageName,
-		ava_services.LOG_LEVEL_DEBUG,
-		2,
-		2,
-		serviceConfigs,
-		desiredServices,
-	)
-}
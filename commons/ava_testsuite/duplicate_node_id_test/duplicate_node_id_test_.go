@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
-	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/verifier"
 	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
 	"github.com/kurtosis-tech/kurtosis/commons/networks"
@@ -14,7 +13,6 @@ import (
 )
 
 const (
-	normalNodeConfigId networks.ConfigurationID = "normal-config"
 	sameCertConfigId networks.ConfigurationID = "same-cert-config"
 
 	vanillaNodeServiceId networks.ServiceID = "vanilla-node"
@@ -23,8 +21,10 @@ const (
 )
 
 type DuplicateNodeIdTest struct {
-	ImageName string
-	Verifier  verifier.NetworkStateVerifier
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
+	Verifier       verifier.NetworkStateVerifier
 }
 
 func (test DuplicateNodeIdTest) Run(network networks.Network, context testsuite.TestContext) {
@@ -106,6 +106,9 @@ func (test DuplicateNodeIdTest) Run(network networks.Network, context testsuite.
 		context.Fatal(stacktrace.Propagate(err, "Could not get node ID from first dupe node ID service with ID %v", badServiceId2))
 	}
 	allNodeIds[badServiceId2] = badServiceNodeId2
+	context.AssertTrue(
+		badServiceNodeId1 == badServiceNodeId2,
+		stacktrace.NewError("Expected services %v and %v to have the same node ID, but they have %v and %v", badServiceId1, badServiceId2, badServiceNodeId1, badServiceNodeId2))
 	logrus.Info("Second node added, causing duplicate node ID")
 
 	// At this point, it's undefined what happens with the two nodes with duplicate IDs; verify that the original nodes
@@ -159,35 +162,7 @@ func (test DuplicateNodeIdTest) Run(network networks.Network, context testsuite.
 }
 
 func (test DuplicateNodeIdTest) GetNetworkLoader() (networks.NetworkLoader, error) {
-	serviceConfigs := map[networks.ConfigurationID]ava_networks.TestGeckoNetworkServiceConfig{
-		normalNodeConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(
-			true,
-			ava_services.LOG_LEVEL_DEBUG,
-			test.ImageName,
-			2,
-			2,
-			make(map[string]string),
-		),
-		sameCertConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(
-			false,
-			ava_services.LOG_LEVEL_DEBUG,
-			test.ImageName,
-			2,
-			2,
-			make(map[string]string),
-		),
-	}
-	desiredServices := map[networks.ServiceID]networks.ConfigurationID{
-		vanillaNodeServiceId: normalNodeConfigId,
-	}
-	return ava_networks.NewTestGeckoNetworkLoader(
-		true,
-		test.ImageName,
-		ava_services.LOG_LEVEL_DEBUG,
-		2,
-		2,
-		serviceConfigs,
-		desiredServices)
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
 }
 
 func (test DuplicateNodeIdTest) GetExecutionTimeout() time.Duration {
@@ -195,7 +170,7 @@ func (test DuplicateNodeIdTest) GetExecutionTimeout() time.Duration {
 }
 
 func (test DuplicateNodeIdTest) GetSetupBuffer() time.Duration {
-	// TODO drop this when the availabilityChecker doesn't have a sleep (because we spin up a bunch of nodes before execution)
+	// TODO drop this once the bootstrap check in the availability checker has been proven against the pinned Gecko image
 	return 6 * time.Minute
 }
 
@@ -0,0 +1,28 @@
+package ava_testsuite
+
+import (
+	"context"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+)
+
+/*
+Wraps a test so that every Gecko client it gets from its network is bound to a context which expires after the test's
+execution timeout, so that a hung node can't keep a request in flight past the end of the test
+*/
+type executionContextBoundTest struct {
+	testsuite.Test
+}
+
+func (test executionContextBoundTest) Run(network networks.Network, testContext testsuite.TestContext) {
+	castedNetwork, ok := network.(ava_networks.TestGeckoNetwork)
+	if !ok {
+		testContext.Fatal(stacktrace.NewError("Expected the test's network to be a TestGeckoNetwork, but got a %T", network))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), test.GetExecutionTimeout())
+	defer cancel()
+	test.Test.Run(castedNetwork.WithContext(ctx), testContext)
+}
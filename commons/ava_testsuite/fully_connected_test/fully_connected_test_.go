@@ -4,7 +4,6 @@ import (
 	"time"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
-	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/verifier"
 	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
@@ -19,16 +18,16 @@ const (
 	seedAmount     = int64(50000000000000)
 	stakeAmount    = int64(30000000000000)
 
-	normalNodeConfigId networks.ConfigurationID = "normal-config"
-
 	networkAcceptanceTimeoutRatio                    = 0.3
 	nonBootValidatorServiceId     networks.ServiceID = "validator-service"
 	nonBootNonValidatorServiceId  networks.ServiceID = "non-validator-service"
 )
 
 type StakingNetworkFullyConnectedTest struct {
-	ImageName string
-	Verifier  verifier.NetworkStateVerifier
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
+	Verifier       verifier.NetworkStateVerifier
 }
 
 func (test StakingNetworkFullyConnectedTest) Run(network networks.Network, context testsuite.TestContext) {
@@ -73,24 +72,13 @@ func (test StakingNetworkFullyConnectedTest) Run(network networks.Network, conte
 	if err := test.Verifier.VerifyNetworkFullyConnected(allServiceIds, stakerIds, allNodeIds, allGeckoClients); err != nil {
 		context.Fatal(stacktrace.Propagate(err, "An error occurred verifying that the network is fully connected after gossip"))
 	}
+	if err := test.Verifier.VerifyNetworkHealthy(allGeckoClients); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "An error occurred verifying that the network is healthy after gossip"))
+	}
 }
 
 func (test StakingNetworkFullyConnectedTest) GetNetworkLoader() (networks.NetworkLoader, error) {
-	serviceConfigs := map[networks.ConfigurationID]ava_networks.TestGeckoNetworkServiceConfig{
-		normalNodeConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(true, ava_services.LOG_LEVEL_DEBUG, test.ImageName, 2, 2, make(map[string]string)),
-	}
-	desiredServices := map[networks.ServiceID]networks.ConfigurationID{
-		nonBootValidatorServiceId:    normalNodeConfigId,
-		nonBootNonValidatorServiceId: normalNodeConfigId,
-	}
-	return ava_networks.NewTestGeckoNetworkLoader(
-		true,
-		test.ImageName,
-		ava_services.LOG_LEVEL_DEBUG,
-		2,
-		2,
-		serviceConfigs,
-		desiredServices)
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
 }
 
 func (test StakingNetworkFullyConnectedTest) GetExecutionTimeout() time.Duration {
@@ -98,7 +86,7 @@ func (test StakingNetworkFullyConnectedTest) GetExecutionTimeout() time.Duration
 }
 
 func (test StakingNetworkFullyConnectedTest) GetSetupBuffer() time.Duration {
-	// TODO drop this when the availabilityChecker doesn't have a sleep (because we spin up a bunch of nodes before running the test)
+	// TODO drop this once the bootstrap check in the availability checker has been proven against the pinned Gecko image
 	return 6 * time.Minute
 }
 
@@ -0,0 +1,136 @@
+package keystore_migration_test
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/string_utils"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	migratedUsername = "migrated_user"
+	migratedPassword = "test34test!23"
+	seedAmount       = int64(50000000000000)
+	transferAmount   = int64(10000000000000)
+
+	sourceNodeServiceId      networks.ServiceID = "source-node"
+	destinationNodeServiceId networks.ServiceID = "destination-node"
+
+	networkAcceptanceTimeoutRatio = 0.3
+)
+
+/*
+Funds a user on one node, moves the user to another node by exporting and importing them through the keystore, and
+verifies that the destination node can spend the user's funds with the migrated keys
+*/
+type StakingNetworkKeystoreMigrationTest struct {
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
+}
+
+func (test StakingNetworkKeystoreMigrationTest) Run(network networks.Network, context testsuite.TestContext) {
+	castedNetwork := network.(ava_networks.TestGeckoNetwork)
+	networkAcceptanceTimeout := time.Duration(networkAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
+
+	sourceClient, err := castedNetwork.GetGeckoClient(sourceNodeServiceId)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get source node client"))
+	}
+	destinationClient, err := castedNetwork.GetGeckoClient(destinationNodeServiceId)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get destination node client"))
+	}
+
+	// ============================= FUND USER ON SOURCE NODE =============================
+	sourceRunner := rpc_workflow_runner.NewRpcWorkflowRunner(
+		sourceClient,
+		migratedUsername,
+		migratedPassword,
+		networkAcceptanceTimeout)
+	fundedAddress, err := sourceRunner.CreateAndSeedXChainAccountFromGenesis(seedAmount)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not seed XChain account from Genesis."))
+	}
+
+	// ============================= MIGRATE USER =============================
+	exportedUser, err := sourceClient.KeystoreApi().ExportUser(migratedUsername, migratedPassword)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not export user %v from source node", migratedUsername))
+	}
+	if _, err := destinationClient.KeystoreApi().ImportUser(migratedUsername, migratedPassword, exportedUser); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not import user %v into destination node", migratedUsername))
+	}
+
+	destinationUsers, err := destinationClient.KeystoreApi().ListUsers()
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not list users of destination node"))
+	}
+	context.AssertTrue(
+		string_utils.Contains(destinationUsers, migratedUsername),
+		stacktrace.NewError("Destination node users %v don't contain migrated user %v", destinationUsers, migratedUsername))
+
+	destinationAddresses, err := destinationClient.XChainApi().ListAddresses(migratedUsername, migratedPassword)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not list addresses of migrated user on destination node"))
+	}
+	context.AssertTrue(
+		string_utils.Contains(destinationAddresses, fundedAddress),
+		stacktrace.NewError("Migrated user's addresses %v don't contain funded address %v", destinationAddresses, fundedAddress))
+
+	// ============================= SPEND FROM DESTINATION NODE =============================
+	// Only a node holding the user's keys can sign this transaction, so its acceptance proves the keys were migrated
+	recipientAddress, err := destinationClient.XChainApi().CreateAddress(migratedUsername, migratedPassword)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not create recipient address on destination node"))
+	}
+	txId, err := destinationClient.XChainApi().Send(
+		transferAmount,
+		rpc_workflow_runner.AVA_ASSET_ID,
+		recipientAddress,
+		migratedUsername,
+		migratedPassword)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not send AVA from migrated user on destination node"))
+	}
+	if err := rpc_workflow_runner.WaitForXchainTransactionAcceptance(destinationClient, txId, networkAcceptanceTimeout); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Transfer from migrated user was never accepted"))
+	}
+	recipientBalance, err := sourceClient.XChainApi().GetBalance(recipientAddress, rpc_workflow_runner.AVA_ASSET_ID)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get balance of recipient address %v", recipientAddress))
+	}
+	expectedRecipientBalance := strconv.FormatInt(transferAmount, 10)
+	context.AssertTrue(
+		recipientBalance.Balance == expectedRecipientBalance,
+		stacktrace.NewError("Actual recipient balance, %v, != expected recipient balance, %v", recipientBalance.Balance, expectedRecipientBalance))
+
+	// ============================= DELETE USER FROM SOURCE NODE =============================
+	if _, err := sourceClient.KeystoreApi().DeleteUser(migratedUsername, migratedPassword); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not delete user %v from source node", migratedUsername))
+	}
+	sourceUsers, err := sourceClient.KeystoreApi().ListUsers()
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not list users of source node"))
+	}
+	context.AssertTrue(
+		!string_utils.Contains(sourceUsers, migratedUsername),
+		stacktrace.NewError("Source node users %v still contain deleted user %v", sourceUsers, migratedUsername))
+}
+
+func (test StakingNetworkKeystoreMigrationTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
+}
+
+func (test StakingNetworkKeystoreMigrationTest) GetExecutionTimeout() time.Duration {
+	return 3 * time.Minute
+}
+
+func (test StakingNetworkKeystoreMigrationTest) GetSetupBuffer() time.Duration {
+	return 3 * time.Minute
+}
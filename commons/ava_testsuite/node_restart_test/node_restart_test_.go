@@ -0,0 +1,246 @@
+package node_restart_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/string_utils"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	username   = "restarted_user"
+	password   = "test34test!23"
+	seedAmount = int64(50000000000000)
+
+	observerNodeServiceId networks.ServiceID = "observer-node"
+
+	networkAcceptanceTimeoutRatio = 0.3
+	peerPollInterval              = 2 * time.Second
+)
+
+/*
+Stops a boot node (which is a validator from genesis) gracefully and starts it again, then kills it as if it crashed and
+starts it again, verifying each time that it comes back with the same node ID and the state in its database, is still a
+validator, and rejoins its peers; then pauses and unpauses it, verifying that it recovers from hanging
+*/
+type StakingNetworkNodeRestartTest struct {
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
+}
+
+func (test StakingNetworkNodeRestartTest) Run(network networks.Network, context testsuite.TestContext) {
+	castedNetwork := network.(ava_networks.TestGeckoNetwork)
+	networkAcceptanceTimeout := time.Duration(networkAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
+
+	// The last boot node is restarted, because it bootstraps from all the others so can rejoin the network through any of them
+	bootServiceIds := []string{}
+	for serviceId := range castedNetwork.GetAllBootServiceIds() {
+		bootServiceIds = append(bootServiceIds, string(serviceId))
+	}
+	sort.Strings(bootServiceIds)
+	restartedServiceId := networks.ServiceID(bootServiceIds[len(bootServiceIds)-1])
+	restartedClient, err := castedNetwork.GetGeckoClient(restartedServiceId)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get client of restarted service %v", restartedServiceId))
+	}
+	observerClient, err := castedNetwork.GetGeckoClient(observerNodeServiceId)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get observer node client"))
+	}
+
+	// ============================= CREATE STATE TO PERSIST =============================
+	runner := rpc_workflow_runner.NewRpcWorkflowRunner(
+		restartedClient,
+		username,
+		password,
+		networkAcceptanceTimeout)
+	fundedAddress, err := runner.CreateAndSeedXChainAccountFromGenesis(seedAmount)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not seed XChain account from Genesis."))
+	}
+	nodeId, err := restartedClient.InfoApi().GetNodeId()
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get node ID before restart"))
+	}
+	if err := verifyIsValidator(observerClient, nodeId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Service %v isn't a validator before being restarted", restartedServiceId))
+	}
+
+	// ============================= STOP & START =============================
+	logrus.Infof("Stopping service %v...", restartedServiceId)
+	if err := castedNetwork.StopService(restartedServiceId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not stop service %v", restartedServiceId))
+	}
+	logrus.Infof("Starting service %v again...", restartedServiceId)
+	if err := castedNetwork.StartService(restartedServiceId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not start service %v", restartedServiceId))
+	}
+	if err := verifyRecovered(restartedClient, observerClient, nodeId, fundedAddress, networkAcceptanceTimeout); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Service %v didn't recover from being stopped", restartedServiceId))
+	}
+
+	// ============================= KILL & START =============================
+	logrus.Infof("Killing service %v...", restartedServiceId)
+	if err := castedNetwork.KillService(restartedServiceId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not kill service %v", restartedServiceId))
+	}
+	logrus.Infof("Starting service %v again...", restartedServiceId)
+	if err := castedNetwork.StartService(restartedServiceId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not start service %v after killing it", restartedServiceId))
+	}
+	if err := verifyRecovered(restartedClient, observerClient, nodeId, fundedAddress, networkAcceptanceTimeout); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Service %v didn't recover from being killed", restartedServiceId))
+	}
+
+	// ============================= PAUSE & UNPAUSE =============================
+	logrus.Infof("Pausing service %v...", restartedServiceId)
+	if err := castedNetwork.PauseService(restartedServiceId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not pause service %v", restartedServiceId))
+	}
+	logrus.Infof("Unpausing service %v...", restartedServiceId)
+	if err := castedNetwork.UnpauseService(restartedServiceId); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not unpause service %v", restartedServiceId))
+	}
+	if err := waitForBootstrapped(restartedClient, networkAcceptanceTimeout); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Node isn't bootstrapped after being unpaused"))
+	}
+	if err := waitForPeer(observerClient, nodeId, networkAcceptanceTimeout); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Unpaused node isn't in the peer set of the observer node"))
+	}
+}
+
+func (test StakingNetworkNodeRestartTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
+}
+
+func (test StakingNetworkNodeRestartTest) GetExecutionTimeout() time.Duration {
+	return 5 * time.Minute
+}
+
+func (test StakingNetworkNodeRestartTest) GetSetupBuffer() time.Duration {
+	return 3 * time.Minute
+}
+
+func waitForPeer(client *gecko_client.GeckoClient, peerNodeId string, timeout time.Duration) error {
+	peerPoller := poller.NewPoller(
+		fmt.Sprintf("peer %s to be connected", peerNodeId),
+		poller.NewConstantBackoff(peerPollInterval))
+	ctx, cancel := context.WithTimeout(client.GetContext(), timeout)
+	defer cancel()
+	_, err := peerPoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			peers, err := client.InfoApi().GetPeers()
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Failed to get peers")
+			}
+			for _, peer := range peers {
+				if peer.Id == peerNodeId {
+					return true, nil
+				}
+			}
+			return false, nil
+		},
+		poller.Equals(true))
+	if err != nil {
+		return stacktrace.Propagate(err, "Node %s never became a peer", peerNodeId)
+	}
+	return nil
+}
+
+// Waits for a node to report the XChain as bootstrapped, which it may not do straight away after hanging
+func waitForBootstrapped(client *gecko_client.GeckoClient, timeout time.Duration) error {
+	bootstrapPoller := poller.NewPoller(
+		"XChain to be bootstrapped",
+		poller.NewConstantBackoff(peerPollInterval))
+	ctx, cancel := context.WithTimeout(client.GetContext(), timeout)
+	defer cancel()
+	_, err := bootstrapPoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			isBootstrapped, err := client.InfoApi().IsBootstrapped(ava_services.XCHAIN_ALIAS)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Failed to get bootstrap status")
+			}
+			return isBootstrapped, nil
+		},
+		poller.Equals(true))
+	if err != nil {
+		return stacktrace.Propagate(err, "Node never reported the XChain as bootstrapped")
+	}
+	return nil
+}
+
+/*
+Verifies that a node which was restarted came back as the node it was before: with the same node ID, the user and funds
+it had in its database, still a validator, and a peer of the observer node
+
+Args:
+	restartedClient: Client of the restarted node
+	observerClient: Client of a node which wasn't restarted
+	nodeId: The node ID the restarted node had before it was restarted
+	fundedAddress: An address funded through the restarted node before it was restarted
+	timeout: How long to wait for the restarted node to rejoin its peers and agree on balances
+*/
+func verifyRecovered(
+	restartedClient *gecko_client.GeckoClient,
+	observerClient *gecko_client.GeckoClient,
+	nodeId string,
+	fundedAddress string,
+	timeout time.Duration) error {
+	nodeIdAfterRestart, err := restartedClient.InfoApi().GetNodeId()
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not get node ID after restart")
+	}
+	if nodeIdAfterRestart != nodeId {
+		return stacktrace.NewError("Node ID after restart, %v, != node ID before restart, %v", nodeIdAfterRestart, nodeId)
+	}
+
+	// The keystore lives in the node's database, so the user surviving means the database did
+	users, err := restartedClient.KeystoreApi().ListUsers()
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not list users after restart")
+	}
+	if !string_utils.Contains(users, username) {
+		return stacktrace.NewError("Users after restart %v don't contain user %v created before restart", users, username)
+	}
+	if err := rpc_workflow_runner.WaitForXchainBalance(restartedClient, fundedAddress, seedAmount, timeout); err != nil {
+		return stacktrace.Propagate(err, "Balance of address %v after restart doesn't match the balance before restart", fundedAddress)
+	}
+
+	if err := waitForPeer(observerClient, nodeId, timeout); err != nil {
+		return stacktrace.Propagate(err, "Restarted node never rejoined the peer set of the observer node")
+	}
+	if err := verifyIsValidator(observerClient, nodeId); err != nil {
+		return stacktrace.Propagate(err, "The observer node doesn't see the restarted node as a validator")
+	}
+	if err := verifyIsValidator(restartedClient, nodeId); err != nil {
+		return stacktrace.Propagate(err, "The restarted node doesn't see itself as a validator")
+	}
+	return nil
+}
+
+func verifyIsValidator(client *gecko_client.GeckoClient, nodeId string) error {
+	validators, err := client.PChainApi().GetCurrentValidators(nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not get current validators")
+	}
+	for _, validator := range validators {
+		if validator.Id == nodeId {
+			return nil
+		}
+	}
+	return stacktrace.NewError("Node %v isn't among the %v current validators", nodeId, len(validators))
+}
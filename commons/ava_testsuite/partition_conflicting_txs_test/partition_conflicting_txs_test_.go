@@ -0,0 +1,190 @@
+package partition_conflicting_txs_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	username       = "partitioned_user"
+	password       = "test34test!23"
+	seedAmount     = int64(50000000000000)
+	transferAmount = int64(10000000000000)
+
+	sideANodeServiceId networks.ServiceID = "side-a-node"
+	sideBNodeServiceId networks.ServiceID = "side-b-node"
+
+	networkAcceptanceTimeoutRatio = 0.3
+	pollInterval                  = 2 * time.Second
+
+	transactionProcessingStatus = "Processing"
+)
+
+/*
+Splits the network in two, issues conflicting transactions spending the same UTXO on each side of the partition, then
+heals the partition and verifies that the transactions were never both accepted
+*/
+type StakingNetworkPartitionConflictingTxsTest struct {
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
+}
+
+func (test StakingNetworkPartitionConflictingTxsTest) Run(network networks.Network, context testsuite.TestContext) {
+	castedNetwork := network.(ava_networks.TestGeckoNetwork)
+	networkAcceptanceTimeout := time.Duration(networkAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
+
+	sideAClient, err := castedNetwork.GetGeckoClient(sideANodeServiceId)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get side A node client"))
+	}
+	sideBClient, err := castedNetwork.GetGeckoClient(sideBNodeServiceId)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get side B node client"))
+	}
+
+	// ============================= FUND USER ON BOTH SIDES =============================
+	sideARunner := rpc_workflow_runner.NewRpcWorkflowRunner(
+		sideAClient,
+		username,
+		password,
+		networkAcceptanceTimeout)
+	fundedAddress, err := sideARunner.CreateAndSeedXChainAccountFromGenesis(seedAmount)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not seed XChain account from Genesis."))
+	}
+	exportedUser, err := sideAClient.KeystoreApi().ExportUser(username, password)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not export user %v from side A node", username))
+	}
+	if _, err := sideBClient.KeystoreApi().ImportUser(username, password, exportedUser); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not import user %v into side B node", username))
+	}
+	// Side B must know about the funds before it's cut off, else it can't issue a transaction spending them
+	if err := rpc_workflow_runner.WaitForXchainBalance(sideBClient, fundedAddress, seedAmount, networkAcceptanceTimeout); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Side B node never saw the seeded funds"))
+	}
+
+	sideARecipient, err := sideAClient.XChainApi().CreateAddress(username, password)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not create recipient address on side A node"))
+	}
+	sideBRecipient, err := sideBClient.XChainApi().CreateAddress(username, password)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not create recipient address on side B node"))
+	}
+
+	// ============================= PARTITION & ISSUE CONFLICTING TXS =============================
+	sideAGroup, sideBGroup := splitBootNodes(castedNetwork.GetAllBootServiceIds())
+	sideAGroup[sideANodeServiceId] = true
+	sideBGroup[sideBNodeServiceId] = true
+	logrus.Infof("Partitioning network into %v and %v", sideAGroup, sideBGroup)
+	if err := castedNetwork.Partition([]map[networks.ServiceID]bool{sideAGroup, sideBGroup}); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not partition network"))
+	}
+	// If the test fails before healing, the rules die with the containers anyways
+	defer castedNetwork.HealPartition()
+
+	// The user only has the one UTXO from the seeding transaction, so both transactions must spend it
+	sideATxId, err := sideAClient.XChainApi().Send(transferAmount, rpc_workflow_runner.AVA_ASSET_ID, sideARecipient, username, password)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not send transaction on side A"))
+	}
+	sideBTxId, err := sideBClient.XChainApi().Send(transferAmount, rpc_workflow_runner.AVA_ASSET_ID, sideBRecipient, username, password)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not send transaction on side B"))
+	}
+	logrus.Infof("Issued conflicting transactions %v on side A and %v on side B", sideATxId, sideBTxId)
+
+	// ============================= HEAL & CHECK SAFETY =============================
+	if err := castedNetwork.HealPartition(); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not heal network partition"))
+	}
+	txIdsToCheck := map[string]gecko_client.XChainApi{
+		sideATxId: sideAClient.XChainApi(),
+		sideBTxId: sideBClient.XChainApi(),
+	}
+	txStatuses := map[string]string{}
+	for txId, xChainApi := range txIdsToCheck {
+		status, err := waitForTxDecision(castedNetwork.GetContext(), xChainApi, txId, networkAcceptanceTimeout)
+		if err != nil {
+			// Neither transaction being decided is a liveness problem, not a safety one, so it doesn't fail the test
+			logrus.Warnf("Transaction %v was never decided: %v", txId, err)
+		}
+		txStatuses[txId] = status
+	}
+	logrus.Infof("Statuses of conflicting transactions after healing: %v", txStatuses)
+	context.AssertTrue(
+		txStatuses[sideATxId] != rpc_workflow_runner.TRANSACTION_ACCEPTED_STATUS || txStatuses[sideBTxId] != rpc_workflow_runner.TRANSACTION_ACCEPTED_STATUS,
+		stacktrace.NewError("Conflicting transactions %v and %v were both accepted", sideATxId, sideBTxId))
+}
+
+func (test StakingNetworkPartitionConflictingTxsTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
+}
+
+func (test StakingNetworkPartitionConflictingTxsTest) GetExecutionTimeout() time.Duration {
+	return 5 * time.Minute
+}
+
+func (test StakingNetworkPartitionConflictingTxsTest) GetSetupBuffer() time.Duration {
+	return 3 * time.Minute
+}
+
+// Splits the boot nodes into two groups, with the first group getting the extra node if there's an odd number
+func splitBootNodes(bootServiceIds map[networks.ServiceID]bool) (map[networks.ServiceID]bool, map[networks.ServiceID]bool) {
+	sortedIds := []string{}
+	for serviceId := range bootServiceIds {
+		sortedIds = append(sortedIds, string(serviceId))
+	}
+	sort.Strings(sortedIds)
+
+	firstGroup := make(map[networks.ServiceID]bool)
+	secondGroup := make(map[networks.ServiceID]bool)
+	firstGroupSize := (len(sortedIds) + 1) / 2
+	for i, serviceId := range sortedIds {
+		if i < firstGroupSize {
+			firstGroup[networks.ServiceID(serviceId)] = true
+		} else {
+			secondGroup[networks.ServiceID(serviceId)] = true
+		}
+	}
+	return firstGroup, secondGroup
+}
+
+// Waits for a transaction to be accepted or rejected, or for the given context to be done, returning its last known status
+func waitForTxDecision(ctx context.Context, xChainApi gecko_client.XChainApi, txId string, timeout time.Duration) (string, error) {
+	statusPoller := poller.NewPoller(
+		fmt.Sprintf("decision on transaction %s", txId),
+		poller.NewConstantBackoff(pollInterval))
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	lastStatus := transactionProcessingStatus
+	_, err := statusPoller.PollUntil(
+		timeoutCtx,
+		func() (interface{}, error) {
+			status, err := xChainApi.GetTxStatus(txId)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Failed to get status of transaction %s", txId)
+			}
+			logrus.Debugf("Status of transaction with ID %s: %s", txId, status)
+			lastStatus = status
+			return status, nil
+		},
+		poller.Not(poller.Equals(transactionProcessingStatus)))
+	if err != nil {
+		return lastStatus, stacktrace.Propagate(err, "Transaction %s was never decided", txId)
+	}
+	return lastStatus, nil
+}
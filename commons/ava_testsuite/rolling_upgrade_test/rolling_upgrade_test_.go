@@ -0,0 +1,252 @@
+package rolling_upgrade_test
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/verifier"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	senderUsername    = "upgrade_sender"
+	senderPassword    = "test34test!23"
+	recipientUsername = "upgrade_recipient"
+	recipientPassword = "test34test!23"
+	seedAmount        = int64(50000000000000)
+	transferAmount    = int64(1000000000000)
+
+	senderNodeServiceId    networks.ServiceID = "sender-node"
+	recipientNodeServiceId networks.ServiceID = "recipient-node"
+
+	newNodeConfigId networks.ConfigurationID = "new-config"
+
+	// Each step waits for several things, so gets a smaller share of the timeout than in other tests
+	stepTimeoutRatio = 0.05
+	pollInterval     = 2 * time.Second
+)
+
+/*
+Starts a network on the old Gecko image, then upgrades its nodes to the new image one at a time, verifying after each
+upgrade that the upgraded node reports the new version (as its peers see it), that the network is still fully
+connected, still accepts transactions, and that every node agrees on balances
+
+NOTE: The new image must report a different node version than the old one, so that the upgrade can be observed
+*/
+type StakingNetworkRollingUpgradeTest struct {
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
+	NewImageName   string
+	Verifier       verifier.NetworkStateVerifier
+}
+
+func (test StakingNetworkRollingUpgradeTest) Run(network networks.Network, context testsuite.TestContext) {
+	castedNetwork := network.(ava_networks.TestGeckoNetwork)
+	stepTimeout := time.Duration(stepTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
+
+	stakerIds := castedNetwork.GetAllBootServiceIds()
+	allServiceIds := make(map[networks.ServiceID]bool)
+	for stakerId := range stakerIds {
+		allServiceIds[stakerId] = true
+	}
+	allServiceIds[senderNodeServiceId] = true
+	allServiceIds[recipientNodeServiceId] = true
+
+	allNodeIds, allGeckoClients, err := getNodeIdsAndClients(castedNetwork, allServiceIds)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not get node IDs and clients before upgrading"))
+	}
+	expectedVersions := make(map[networks.ServiceID]string)
+	for serviceId, client := range allGeckoClients {
+		version, err := client.InfoApi().GetNodeVersion()
+		if err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Could not get the node version of service %v before upgrading", serviceId))
+		}
+		expectedVersions[serviceId] = version
+	}
+	// The version the new image reports isn't known until the first node has been upgraded to it
+	newVersion := ""
+
+	// ============================= SET UP ACCOUNTS =============================
+	senderRunner := rpc_workflow_runner.NewRpcWorkflowRunner(
+		allGeckoClients[senderNodeServiceId],
+		senderUsername,
+		senderPassword,
+		stepTimeout)
+	if _, err := senderRunner.CreateAndSeedXChainAccountFromGenesis(seedAmount); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not seed XChain account from Genesis."))
+	}
+	recipientClient := allGeckoClients[recipientNodeServiceId]
+	if _, err := recipientClient.KeystoreApi().CreateUser(recipientUsername, recipientPassword); err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not create recipient user"))
+	}
+	recipientAddress, err := recipientClient.XChainApi().CreateAddress(recipientUsername, recipientPassword)
+	if err != nil {
+		context.Fatal(stacktrace.Propagate(err, "Could not create recipient address"))
+	}
+
+	// ============================= UPGRADE NODES ONE AT A TIME =============================
+	upgradeOrder := []string{}
+	for serviceId := range allServiceIds {
+		upgradeOrder = append(upgradeOrder, string(serviceId))
+	}
+	sort.Strings(upgradeOrder)
+	for stepIdx, serviceIdStr := range upgradeOrder {
+		serviceId := networks.ServiceID(serviceIdStr)
+		logrus.Infof("Upgrading service %v to image %v (step %v of %v)...", serviceId, test.NewImageName, stepIdx+1, len(upgradeOrder))
+		if err := castedNetwork.UpgradeService(serviceId, newNodeConfigId); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Could not upgrade service %v", serviceId))
+		}
+
+		// The upgraded service has a new IP, so all clients need to be fetched again
+		upgradedNodeIds, upgradedGeckoClients, err := getNodeIdsAndClients(castedNetwork, allServiceIds)
+		if err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Could not get node IDs and clients after upgrading service %v", serviceId))
+		}
+		context.AssertTrue(
+			upgradedNodeIds[serviceId] == allNodeIds[serviceId],
+			stacktrace.NewError("Service %v had node ID %v before upgrading but %v after", serviceId, allNodeIds[serviceId], upgradedNodeIds[serviceId]))
+
+		upgradedVersion, err := upgradedGeckoClients[serviceId].InfoApi().GetNodeVersion()
+		if err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Could not get the node version of service %v after upgrading it", serviceId))
+		}
+		if newVersion == "" {
+			context.AssertTrue(
+				upgradedVersion != expectedVersions[serviceId],
+				stacktrace.NewError("Service %v still reports version %v after being upgraded to image %v", serviceId, upgradedVersion, test.NewImageName))
+			newVersion = upgradedVersion
+		}
+		expectedVersions[serviceId] = newVersion
+
+		if err := test.waitForFullyConnected(castedNetwork.GetContext(), allServiceIds, stakerIds, allNodeIds, upgradedGeckoClients, stepTimeout); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Network wasn't fully connected after upgrading service %v", serviceId))
+		}
+		if err := test.waitForNodeVersions(castedNetwork.GetContext(), expectedVersions, allNodeIds, upgradedGeckoClients, stepTimeout); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Nodes didn't report the expected versions after upgrading service %v", serviceId))
+		}
+
+		// The sender's keystore lives in its database, so this also checks that the database survived the upgrade
+		senderClient := upgradedGeckoClients[senderNodeServiceId]
+		txId, err := senderClient.XChainApi().Send(
+			transferAmount,
+			rpc_workflow_runner.AVA_ASSET_ID,
+			recipientAddress,
+			senderUsername,
+			senderPassword)
+		if err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Could not send transaction after upgrading service %v", serviceId))
+		}
+		if err := rpc_workflow_runner.WaitForXchainTransactionAcceptance(senderClient, txId, stepTimeout); err != nil {
+			context.Fatal(stacktrace.Propagate(err, "Transaction sent after upgrading service %v was never accepted", serviceId))
+		}
+
+		expectedRecipientBalance := transferAmount * int64(stepIdx+1)
+		for checkedServiceId, client := range upgradedGeckoClients {
+			if err := rpc_workflow_runner.WaitForXchainBalance(client, recipientAddress, expectedRecipientBalance, stepTimeout); err != nil {
+				context.Fatal(stacktrace.Propagate(
+					err,
+					"Service %v doesn't agree on the recipient's balance after upgrading service %v",
+					checkedServiceId,
+					serviceId))
+			}
+		}
+		logrus.Infof("Network is healthy after upgrading service %v", serviceId)
+	}
+}
+
+func (test StakingNetworkRollingUpgradeTest) GetNetworkLoader() (networks.NetworkLoader, error) {
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
+}
+
+func (test StakingNetworkRollingUpgradeTest) GetExecutionTimeout() time.Duration {
+	return 20 * time.Minute
+}
+
+func (test StakingNetworkRollingUpgradeTest) GetSetupBuffer() time.Duration {
+	return 3 * time.Minute
+}
+
+// Peers reconnect to an upgraded node asynchronously, so the check is retried until it passes or times out
+func (test StakingNetworkRollingUpgradeTest) waitForFullyConnected(
+	ctx context.Context,
+	allServiceIds map[networks.ServiceID]bool,
+	stakerServiceIds map[networks.ServiceID]bool,
+	allNodeIds map[networks.ServiceID]string,
+	allGeckoClients map[networks.ServiceID]*gecko_client.GeckoClient,
+	timeout time.Duration) error {
+	err := pollUntilVerified(ctx, "network to be fully connected", timeout, func() error {
+		return test.Verifier.VerifyNetworkFullyConnected(allServiceIds, stakerServiceIds, allNodeIds, allGeckoClients)
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "Network never became fully connected")
+	}
+	return nil
+}
+
+// Peers only learn an upgraded node's version when they reconnect to it, so the check is retried as well
+func (test StakingNetworkRollingUpgradeTest) waitForNodeVersions(
+	ctx context.Context,
+	expectedVersions map[networks.ServiceID]string,
+	allNodeIds map[networks.ServiceID]string,
+	allGeckoClients map[networks.ServiceID]*gecko_client.GeckoClient,
+	timeout time.Duration) error {
+	err := pollUntilVerified(ctx, "nodes to report the expected versions", timeout, func() error {
+		return test.Verifier.VerifyNodeVersions(expectedVersions, allNodeIds, allGeckoClients)
+	})
+	if err != nil {
+		return stacktrace.Propagate(err, "Nodes never reported the expected versions")
+	}
+	return nil
+}
+
+// Retries the given verification until it passes, the timeout is hit, or the given context is done
+func pollUntilVerified(ctx context.Context, description string, timeout time.Duration, verify func() error) error {
+	verificationPoller := poller.NewPoller(description, poller.NewConstantBackoff(pollInterval))
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, err := verificationPoller.PollUntil(
+		timeoutCtx,
+		func() (interface{}, error) {
+			if err := verify(); err != nil {
+				logrus.Debugf("Still waiting for %v: %v", description, err)
+				return false, nil
+			}
+			return true, nil
+		},
+		poller.Equals(true))
+	if err != nil {
+		return stacktrace.Propagate(err, "Verification never passed")
+	}
+	return nil
+}
+
+func getNodeIdsAndClients(
+	network ava_networks.TestGeckoNetwork,
+	allServiceIds map[networks.ServiceID]bool,
+) (map[networks.ServiceID]string, map[networks.ServiceID]*gecko_client.GeckoClient, error) {
+	allGeckoClients := make(map[networks.ServiceID]*gecko_client.GeckoClient)
+	allNodeIds := make(map[networks.ServiceID]string)
+	for serviceId := range allServiceIds {
+		client, err := network.GetGeckoClient(serviceId)
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "An error occurred getting the Gecko client for service with ID %v", serviceId)
+		}
+		allGeckoClients[serviceId] = client
+		nodeId, err := client.InfoApi().GetNodeId()
+		if err != nil {
+			return nil, nil, stacktrace.Propagate(err, "An error occurred getting the Gecko node ID for service with ID %v", serviceId)
+		}
+		allNodeIds[serviceId] = nodeId
+	}
+	return allNodeIds, allGeckoClients, nil
+}
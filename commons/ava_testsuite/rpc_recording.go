@@ -0,0 +1,63 @@
+package ava_testsuite
+
+import (
+	"os"
+	"path"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	rpcRecordingFileExtension = ".jsonl"
+)
+
+/*
+Wraps a test so that every JSON RPC request its Gecko clients make is recorded to a JSONL file, which can be loaded
+with gecko_client.LoadRpcRecordingsFile and replayed with gecko_client.NewReplayGeckoClient to reproduce a failure
+*/
+type rpcRecordingTest struct {
+	testsuite.Test
+
+	recordingFilepath string
+}
+
+func newRpcRecordingTest(test testsuite.Test, recordingDirpath string, testName string) rpcRecordingTest {
+	return rpcRecordingTest{
+		Test:              test,
+		recordingFilepath: path.Join(recordingDirpath, testName+rpcRecordingFileExtension),
+	}
+}
+
+func (test rpcRecordingTest) Run(network networks.Network, testContext testsuite.TestContext) {
+	castedNetwork, ok := network.(ava_networks.TestGeckoNetwork)
+	if !ok {
+		testContext.Fatal(stacktrace.NewError("Expected the test's network to be a TestGeckoNetwork, but got a %T", network))
+	}
+	recorder, err := createRecorder(test.recordingFilepath)
+	if err != nil {
+		// Recordings are only a debugging aid, so failing to make them shouldn't fail the test
+		logrus.Warnf("Could not record JSON RPC requests; test will run without recording: %v", err)
+		test.Test.Run(network, testContext)
+		return
+	}
+	defer recorder.Close()
+
+	logrus.Infof("Recording JSON RPC requests to %v", test.recordingFilepath)
+	test.Test.Run(castedNetwork.WithRpcRecorder(recorder), testContext)
+}
+
+func createRecorder(recordingFilepath string) (*gecko_client.RpcRecorder, error) {
+	if err := os.MkdirAll(path.Dir(recordingFilepath), os.ModePerm); err != nil {
+		return nil, stacktrace.Propagate(err, "Could not create directory for RPC recordings")
+	}
+	recorder, err := gecko_client.NewFileRpcRecorder(recordingFilepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not create RPC recorder")
+	}
+	return recorder, nil
+}
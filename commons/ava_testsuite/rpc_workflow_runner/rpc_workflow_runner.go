@@ -1,8 +1,11 @@
 package rpc_workflow_runner
 
 import (
+	"context"
+	"fmt"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
 	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 	"strconv"
@@ -21,8 +24,10 @@ const (
 	TIME_UNTIL_DELEGATING_ENDS = 72 * time.Hour
 	DELEGATION_FEE_RATE = 500000
 	XCHAIN_ADDRESS_PREFIX = "X-"
-	NO_IMPORT_INPUTS_ERROR_STR = "problem issuing transaction: no import inputs"
 	IMPORT_AVA_TO_XCHAIN_TIMEOUT = time.Second
+
+	// How long to wait between polls for some state change in the network
+	networkPollInterval = time.Second
 )
 
 /*
@@ -45,6 +50,10 @@ type RpcWorkflowRunner struct {
 		internal state to reflect that acceptance.
 	 */
 	networkAcceptanceTimeout time.Duration
+
+	// How long after issuing staking transactions that staking & delegating begin; only overridden in unit tests
+	timeUntilStakingBegins    time.Duration
+	timeUntilDelegatingBegins time.Duration
 }
 
 func NewRpcWorkflowRunner(
@@ -53,9 +62,11 @@ func NewRpcWorkflowRunner(
 		password string,
 		networkAcceptanceTimeout time.Duration) *RpcWorkflowRunner {
 	return &RpcWorkflowRunner{
-		client:                   client,
-		geckoUser:                NewGeckoUser(username, password),
-		networkAcceptanceTimeout: networkAcceptanceTimeout,
+		client:                    client,
+		geckoUser:                 NewGeckoUser(username, password),
+		networkAcceptanceTimeout:  networkAcceptanceTimeout,
+		timeUntilStakingBegins:    TIME_UNTIL_STAKING_BEGINS,
+		timeUntilDelegatingBegins: TIME_UNTIL_DELEGATING_BEGINS,
 	}
 }
 
@@ -110,7 +121,7 @@ func (runner RpcWorkflowRunner) AddDelegatorOnSubnet(
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to get payer nonce from address %s", pchainAddress)
 	}
-	delegatorStartTime := time.Now().Add(TIME_UNTIL_DELEGATING_BEGINS).Unix()
+	delegatorStartTime := time.Now().Add(runner.timeUntilDelegatingBegins).Unix()
 	addDelegatorUnsignedTxn, err := client.PChainApi().AddDefaultSubnetDelegator(
 		delegateeNodeId,
 		delegatorStartTime,
@@ -148,7 +159,7 @@ func (runner RpcWorkflowRunner) AddValidatorOnSubnet(
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to get payer nonce from address %s", pchainAddress)
 	}
-	stakingStartTime := time.Now().Add(TIME_UNTIL_STAKING_BEGINS).Unix()
+	stakingStartTime := time.Now().Add(runner.timeUntilStakingBegins).Unix()
 	addStakerUnsignedTxn, err := client.PChainApi().AddDefaultSubnetValidator(
 		nodeId,
 		stakingStartTime,
@@ -175,7 +186,10 @@ func (runner RpcWorkflowRunner) AddValidatorOnSubnet(
 	for time.Now().Unix() < stakingStartTime {
 		time.Sleep(time.Second)
 	}
-	runner.waitForValidatorAddition(nodeId, nil)
+	err = runner.waitForValidatorAddition(nodeId, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to wait for %s to be added as a validator.", nodeId)
+	}
 	return nil
 }
 
@@ -261,7 +275,10 @@ func (runner RpcWorkflowRunner) TransferAvaXChainToPChain(
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Failed to issue importAVA transaction.")
 	}
-	runner.waitForPchainNonZeroBalance(pchainAddress)
+	err = runner.waitForPchainNonZeroBalance(pchainAddress)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to wait for PChain address %s to receive funds.", pchainAddress)
+	}
 	return pchainAddress, nil
 }
 
@@ -295,23 +312,33 @@ func (runner RpcWorkflowRunner) TransferAvaPChainToXChain(
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Failed to issue importAVA transaction.")
 	}
-	// XChain API only accepts the XChain address with the xchain prefix.
-	txnId, err := client.XChainApi().ImportAVA(xchainAddress, username, password)
-	for err != nil {
-		/*
-			HACK HACK HACK because the PChain does not have a way to verify transaction acceptence yet,
-			we retry based on the contents of the error message from the XChain call if the pchain transaction
-			has not yet reached consensus
-		*/
-		// TODO When the PChain transaction status endpoint is deployed, use that to wait for transaction acceptance
-		//  (See https://github.com/ava-labs/gecko/issues/296)
-		if strings.Contains(err.Error(), NO_IMPORT_INPUTS_ERROR_STR) {
-			txnId, err = client.XChainApi().ImportAVA(xchainAddress, username, password)
-			time.Sleep(IMPORT_AVA_TO_XCHAIN_TIMEOUT)
-		} else {
-			return "", stacktrace.Propagate(err, "Failed import AVA to xchainAddress %s", xchainAddress)
-		}
-	}
+	/*
+		HACK HACK HACK because the PChain does not have a way to verify transaction acceptence yet,
+		we retry based on the error from the XChain call if the pchain transaction has not yet reached consensus
+	*/
+	// TODO When the PChain transaction status endpoint is deployed, use that to wait for transaction acceptance
+	//  (See https://github.com/ava-labs/gecko/issues/296)
+	importPoller := poller.NewPoller(
+		fmt.Sprintf("import of AVA to XChain address %s", xchainAddress),
+		poller.NewConstantBackoff(IMPORT_AVA_TO_XCHAIN_TIMEOUT))
+	ctx, cancel := context.WithTimeout(runner.client.GetContext(), runner.networkAcceptanceTimeout)
+	defer cancel()
+	importResult, err := importPoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			// XChain API only accepts the XChain address with the xchain prefix.
+			txnId, err := client.XChainApi().ImportAVA(xchainAddress, username, password)
+			if gecko_client.IsRpcError(err, gecko_client.ErrNoImportInputs) {
+				// The PChain export hasn't been accepted yet, so there's nothing to import
+				return "", nil
+			}
+			return txnId, err
+		},
+		poller.Not(poller.Equals("")))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed import AVA to xchainAddress %s", xchainAddress)
+	}
+	txnId := importResult.(string)
 	err = runner.waitForXchainTransactionAcceptance(txnId)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Failed to wait for acceptance of transaction on XChain.")
@@ -320,46 +347,96 @@ func (runner RpcWorkflowRunner) TransferAvaPChainToXChain(
 }
 
 func (runner RpcWorkflowRunner) waitForXchainTransactionAcceptance(txnId string) error {
-	client := runner.client
-	status, err := client.XChainApi().GetTxStatus(txnId)
-	if err != nil {
-		return stacktrace.Propagate(err,"Failed to get status.")
-	}
-	pollStartTime := time.Now()
-	for i := 0; time.Since(pollStartTime) < runner.networkAcceptanceTimeout && status != TRANSACTION_ACCEPTED_STATUS; i++ {
-		status, err = client.XChainApi().GetTxStatus(txnId)
-		if err != nil {
-			return stacktrace.Propagate(err,"Failed to get status.")
-		}
-		logrus.Debugf("Status for transaction %s: %s", txnId, status)
-		time.Sleep(time.Second)
+	return WaitForXchainTransactionAcceptance(runner.client, txnId, runner.networkAcceptanceTimeout)
+}
+
+/*
+Polls the node behind the given client until it reports the given transaction as accepted on the XChain
+
+Args:
+	client: Client of the node to poll, whose context the wait is also bound to
+	txnId: ID of the transaction to wait for
+	timeout: How long to wait before giving up on the transaction being accepted
+*/
+func WaitForXchainTransactionAcceptance(client *gecko_client.GeckoClient, txnId string, timeout time.Duration) error {
+	xChainApi := client.XChainApi()
+	statusPoller := poller.NewPoller(
+		fmt.Sprintf("acceptance of transaction %s on the XChain", txnId),
+		poller.NewConstantBackoff(networkPollInterval))
+	ctx, cancel := context.WithTimeout(client.GetContext(), timeout)
+	defer cancel()
+	_, err := statusPoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			status, err := xChainApi.GetTxStatus(txnId)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Failed to get status.")
+			}
+			logrus.Debugf("Status for transaction %s: %s", txnId, status)
+			return status, nil
+		},
+		poller.Equals(TRANSACTION_ACCEPTED_STATUS))
+	if err != nil {
+		return stacktrace.Propagate(err, "Transaction %s was not accepted on the XChain.", txnId)
 	}
-	if status != TRANSACTION_ACCEPTED_STATUS {
-		return stacktrace.NewError("Timed out waiting for transaction %s to be accepted on the XChain.", txnId)
-	} else {
-		return nil
+	return nil
+}
+
+/*
+Polls the node behind the given client until it reports the given AVA balance for an address on the XChain
+
+Args:
+	client: Client of the node to poll, whose context the wait is also bound to
+	address: XChain address whose balance to check
+	expectedBalance: AVA balance the address must reach
+	timeout: How long to wait before giving up on the address reaching the balance
+*/
+func WaitForXchainBalance(client *gecko_client.GeckoClient, address string, expectedBalance int64, timeout time.Duration) error {
+	xChainApi := client.XChainApi()
+	balancePoller := poller.NewPoller(
+		fmt.Sprintf("balance of address %s on the XChain", address),
+		poller.NewConstantBackoff(networkPollInterval))
+	ctx, cancel := context.WithTimeout(client.GetContext(), timeout)
+	defer cancel()
+	_, err := balancePoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			balance, err := xChainApi.GetBalance(address, AVA_ASSET_ID)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Failed to get balance of address %s", address)
+			}
+			return balance.Balance, nil
+		},
+		poller.Equals(strconv.FormatInt(expectedBalance, 10)))
+	if err != nil {
+		return stacktrace.Propagate(err, "Address %s never had balance %v on the XChain.", address, expectedBalance)
 	}
+	return nil
 }
 
 func (runner RpcWorkflowRunner) waitForValidatorAddition(nodeId string, subnetIdPtr *string) error {
 	client := runner.client
-	validators, err := client.PChainApi().GetCurrentValidators(subnetIdPtr)
-	if err != nil {
-		return stacktrace.Propagate(err, "Could not get current validators")
-	}
-	pollStartTime := time.Now()
-	for i := 0; time.Since(pollStartTime) < runner.networkAcceptanceTimeout && !checkValidatorInValidators(nodeId, validators); i++ {
-		time.Sleep(time.Second)
-		validators, err = client.PChainApi().GetCurrentValidators(subnetIdPtr)
-		if err != nil {
-			return stacktrace.Propagate(err, "Could not get current validators")
-		}
-	}
-	if !checkValidatorInValidators(nodeId, validators) {
-		return stacktrace.NewError("Timed out waiting for validator %s to be accepted as a validator by the network.", nodeId)
-	} else {
-		return nil
+	validatorsPoller := poller.NewPoller(
+		fmt.Sprintf("acceptance of %s as a validator by the network", nodeId),
+		poller.NewConstantBackoff(networkPollInterval))
+	ctx, cancel := context.WithTimeout(runner.client.GetContext(), runner.networkAcceptanceTimeout)
+	defer cancel()
+	_, err := validatorsPoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			validators, err := client.PChainApi().GetCurrentValidators(subnetIdPtr)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Could not get current validators")
+			}
+			return validators, nil
+		},
+		func(value interface{}) bool {
+			return checkValidatorInValidators(nodeId, value.([]gecko_client.Validator))
+		})
+	if err != nil {
+		return stacktrace.Propagate(err, "Validator %s was not accepted as a validator by the network.", nodeId)
 	}
+	return nil
 }
 
 func checkValidatorInValidators(nodeId string, validators []gecko_client.Validator) bool {
@@ -373,29 +450,26 @@ func checkValidatorInValidators(nodeId string, validators []gecko_client.Validat
 
 func (runner RpcWorkflowRunner) waitForPchainNonZeroBalance(pchainAddress string) error {
 	client := runner.client
-	pchainAccount, err := client.PChainApi().GetAccount(pchainAddress)
-	if err != nil {
-		return stacktrace.Propagate(err, "Could not get PChain account information")
-	}
-	balance := pchainAccount.Balance
-	if err != nil {
-		return stacktrace.Propagate(err,"Failed to get balance.")
-	}
-	pollStartTime := time.Now()
-	for i := 0; time.Since(pollStartTime) < runner.networkAcceptanceTimeout && balance == "0"; i++ {
-		pchainAccount, err = client.PChainApi().GetAccount(pchainAddress)
-		if err != nil {
-			return stacktrace.Propagate(err,"Failed to get account information.")
-		}
-		balance = pchainAccount.Balance
-		logrus.Debugf("Balance for account %s: %s", pchainAddress, balance)
-		time.Sleep(time.Second)
-	}
-	if balance == "0" {
-		return stacktrace.NewError("Timed out waiting for PChain address %s to receive funds.", pchainAddress)
-	} else {
-		return nil
+	balancePoller := poller.NewPoller(
+		fmt.Sprintf("PChain address %s to receive funds", pchainAddress),
+		poller.NewConstantBackoff(networkPollInterval))
+	ctx, cancel := context.WithTimeout(runner.client.GetContext(), runner.networkAcceptanceTimeout)
+	defer cancel()
+	_, err := balancePoller.PollUntil(
+		ctx,
+		func() (interface{}, error) {
+			pchainAccount, err := client.PChainApi().GetAccount(pchainAddress)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "Failed to get account information.")
+			}
+			logrus.Debugf("Balance for account %s: %s", pchainAddress, pchainAccount.Balance)
+			return pchainAccount.Balance, nil
+		},
+		poller.Not(poller.Equals("0")))
+	if err != nil {
+		return stacktrace.Propagate(err, "PChain address %s did not receive funds.", pchainAddress)
 	}
+	return nil
 }
 
 func (runner RpcWorkflowRunner) getCurrentPayerNonce(pchainAddress string) (int, error) {
@@ -0,0 +1,132 @@
+package rpc_workflow_runner
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client/fake_gecko_node"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testNodeId                   = "7Xhw2mDxuDS44j42TCB6U5579esbSt3Lg"
+	testUsername                 = "test_user"
+	testPassword                 = "test34test!23"
+	testDelegatorUsername        = "delegator_user"
+	testDelegatorPassword        = "delegator34test!23"
+	testNetworkAcceptanceTimeout = 5 * time.Second
+	genesisBalance               = int64(1000000000000000)
+	seedAmount                   = int64(50000000000000)
+	stakeAmount                  = int64(30000000000000)
+	delegatorStakeAmount         = int64(20000000000000)
+)
+
+func newTestNodeAndRunner() (*fake_gecko_node.FakeGeckoNode, *gecko_client.GeckoClient, *RpcWorkflowRunner) {
+	node := fake_gecko_node.NewFakeGeckoNode(
+		testNodeId,
+		map[string]int64{
+			ava_networks.DefaultLocalNetGenesisConfig.FundedAddresses.PrivateKey: genesisBalance,
+		})
+	client := gecko_client.NewGeckoClient(node.GetIpAddr(), node.GetPort())
+	return node, client, newTestRunner(client, testUsername, testPassword)
+}
+
+// Creates a runner which doesn't wait for staking to begin, so workflows don't have to wait in real time
+func newTestRunner(client *gecko_client.GeckoClient, username string, password string) *RpcWorkflowRunner {
+	runner := NewRpcWorkflowRunner(client, username, password, testNetworkAcceptanceTimeout)
+	runner.timeUntilStakingBegins = 0
+	runner.timeUntilDelegatingBegins = 0
+	return runner
+}
+
+func TestGetFundsAndStartValidating(t *testing.T) {
+	node, client, runner := newTestNodeAndRunner()
+	defer node.Close()
+
+	err := runner.GetFundsAndStartValidating(seedAmount, stakeAmount)
+	assert.NoError(t, err)
+
+	validators, err := client.PChainApi().GetCurrentValidators(nil)
+	assert.NoError(t, err)
+	assert.True(t, checkValidatorInValidators(testNodeId, validators))
+	if assert.Equal(t, 1, len(validators)) {
+		assert.Equal(t, strconv.FormatInt(stakeAmount, 10), validators[0].StakeAmount)
+	}
+
+	accounts, err := client.PChainApi().ListAccounts(testUsername, testPassword)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(accounts)) {
+		assert.Equal(t, strconv.FormatInt(seedAmount-stakeAmount, 10), accounts[0].Balance)
+	}
+}
+
+func TestTransferAvaBetweenChains(t *testing.T) {
+	node, client, runner := newTestNodeAndRunner()
+	defer node.Close()
+
+	_, err := runner.CreateAndSeedXChainAccountFromGenesis(seedAmount)
+	assert.NoError(t, err)
+	pchainAddress, err := runner.TransferAvaXChainToPChain(seedAmount)
+	assert.NoError(t, err)
+	pchainAccount, err := client.PChainApi().GetAccount(pchainAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.FormatInt(seedAmount, 10), pchainAccount.Balance)
+	assert.Equal(t, "1", pchainAccount.Nonce)
+
+	xchainAddress, err := client.XChainApi().CreateAddress(testUsername, testPassword)
+	assert.NoError(t, err)
+	returnAmount := seedAmount / 2
+	_, err = runner.TransferAvaPChainToXChain(pchainAddress, xchainAddress, returnAmount)
+	assert.NoError(t, err)
+
+	xchainBalance, err := client.XChainApi().GetBalance(xchainAddress, AVA_ASSET_ID)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.FormatInt(returnAmount, 10), xchainBalance.Balance)
+	pchainAccount, err = client.PChainApi().GetAccount(pchainAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.FormatInt(seedAmount-returnAmount, 10), pchainAccount.Balance)
+}
+
+func TestAddDelegatorOnSubnet(t *testing.T) {
+	node, client, stakerRunner := newTestNodeAndRunner()
+	defer node.Close()
+	err := stakerRunner.GetFundsAndStartValidating(seedAmount, stakeAmount)
+	assert.NoError(t, err)
+
+	delegatorRunner := newTestRunner(client, testDelegatorUsername, testDelegatorPassword)
+	_, err = delegatorRunner.CreateAndSeedXChainAccountFromGenesis(seedAmount)
+	assert.NoError(t, err)
+	delegatorPchainAddress, err := delegatorRunner.TransferAvaXChainToPChain(seedAmount)
+	assert.NoError(t, err)
+	err = delegatorRunner.AddDelegatorOnSubnet(testNodeId, delegatorPchainAddress, delegatorStakeAmount)
+	assert.NoError(t, err)
+
+	delegatorAccount, err := client.PChainApi().GetAccount(delegatorPchainAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.FormatInt(seedAmount-delegatorStakeAmount, 10), delegatorAccount.Balance)
+}
+
+func TestSeedingMoreThanGenesisFails(t *testing.T) {
+	node, _, runner := newTestNodeAndRunner()
+	defer node.Close()
+
+	_, err := runner.CreateAndSeedXChainAccountFromGenesis(genesisBalance + 1)
+	assert.True(t, gecko_client.IsRpcError(err, gecko_client.ErrInsufficientFunds))
+}
+
+func TestWaitsStopWhenClientContextIsDone(t *testing.T) {
+	node, client, _ := newTestNodeAndRunner()
+	defer node.Close()
+
+	// As when the test's execution timeout is hit partway through a wait
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	startTime := time.Now()
+	err := WaitForXchainBalance(client.WithContext(ctx), "X-unfunded", seedAmount, time.Minute)
+	assert.Error(t, err)
+	assert.True(t, time.Since(startTime) < testNetworkAcceptanceTimeout, "The wait should end with the client's context, not its own timeout")
+}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
-	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
 	"github.com/kurtosis-tech/kurtosis/commons/networks"
 	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
@@ -26,11 +25,12 @@ const (
 	delegatorNodeServiceId networks.ServiceID = "delegator-node"
 
 	networkAcceptanceTimeoutRatio = 0.3
-	normalNodeConfigId networks.ConfigurationID = "normal-config"
 )
 
 type StakingNetworkRpcWorkflowTest struct {
-	ImageName string
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
 }
 
 func (test StakingNetworkRpcWorkflowTest) Run(network networks.Network, context testsuite.TestContext) {
@@ -125,24 +125,7 @@ func (test StakingNetworkRpcWorkflowTest) Run(network networks.Network, context
 }
 
 func (test StakingNetworkRpcWorkflowTest) GetNetworkLoader() (networks.NetworkLoader, error) {
-	// Define possible service configurations.
-	serviceConfigs := map[networks.ConfigurationID]ava_networks.TestGeckoNetworkServiceConfig{
-		normalNodeConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(true, ava_services.LOG_LEVEL_DEBUG, test.ImageName, 2, 2, make(map[string]string)),
-	}
-	// Define which services use which configurations.
-	desiredServices := map[networks.ServiceID]networks.ConfigurationID{
-		regularNodeServiceId:   normalNodeConfigId,
-		delegatorNodeServiceId: normalNodeConfigId,
-	}
-	// Return a Gecko test net with this service:configuration mapping.
-	return ava_networks.NewTestGeckoNetworkLoader(
-		true,
-		test.ImageName,
-		ava_services.LOG_LEVEL_DEBUG,
-		2,
-		2,
-		serviceConfigs,
-		desiredServices)
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
 }
 
 func (test StakingNetworkRpcWorkflowTest) GetExecutionTimeout() time.Duration {
@@ -150,6 +133,6 @@ func (test StakingNetworkRpcWorkflowTest) GetExecutionTimeout() time.Duration {
 }
 
 func (test StakingNetworkRpcWorkflowTest) GetSetupBuffer() time.Duration {
-	// TODO drop this down when the availability checker doesn't have a sleep (becuase we spin up a bunch of nodes before the test starts executing)
+	// TODO drop this once the bootstrap check in the availability checker has been proven against the pinned Gecko image
 	return 6 * time.Minute
 }
@@ -0,0 +1,185 @@
+package ava_testsuite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+	"github.com/palantir/stacktrace"
+)
+
+// ========= Tags ========================
+const (
+	// The test uses a byzantine Gecko image
+	TAG_BYZANTINE = "byzantine"
+
+	// The test runs a staking network
+	TAG_STAKING = "staking"
+
+	// The test takes much longer than most, so can be left out of quick runs
+	TAG_SLOW = "slow"
+
+	// The test is a quick check that Gecko basically works, suitable for running on every change
+	TAG_SMOKE = "smoke"
+
+	// The test exercises nodes being replaced by nodes running a different Gecko image
+	TAG_UPGRADE = "upgrade"
+)
+
+func GetAllTags() []string {
+	return []string{
+		TAG_BYZANTINE,
+		TAG_STAKING,
+		TAG_SLOW,
+		TAG_SMOKE,
+		TAG_UPGRADE,
+	}
+}
+
+// ========= Required Images Enum ========================
+// The images of the test suite which a test needs in order to run
+type RequiredImage string
+
+const (
+	REQUIRED_IMAGE_NORMAL    RequiredImage = "normal"
+	REQUIRED_IMAGE_BYZANTINE RequiredImage = "byzantine"
+	REQUIRED_IMAGE_UPGRADE   RequiredImage = "upgrade"
+)
+
+// ========= Metadata ========================
+type TestMetadata struct {
+	Description    string
+	Tags           []string
+	RequiredImages []RequiredImage
+}
+
+func (metadata TestMetadata) hasAnyTag(tags map[string]bool) bool {
+	for _, ownTag := range metadata.Tags {
+		if tags[ownTag] {
+			return true
+		}
+	}
+	return false
+}
+
+// A test of the suite, along with how to create it from the suite's configuration
+type avaTestDefinition struct {
+	metadata TestMetadata
+	create   func(suite AvaTestSuite, networkSpec ava_networks.NetworkSpec) testsuite.Test
+}
+
+// ========= Catalog ========================
+type TestCatalogEntry struct {
+	Metadata TestMetadata
+
+	// Why the test can't be run with the suite's configuration; empty if it can be run
+	UnavailableReason string
+}
+
+func (entry TestCatalogEntry) IsAvailable() bool {
+	return entry.UnavailableReason == ""
+}
+
+/*
+Gets every test in the suite, including the ones which GetTests leaves out because the suite lacks an image or network
+spec they need, keyed by test name
+*/
+func (a AvaTestSuite) GetTestCatalog() map[string]TestCatalogEntry {
+	result := make(map[string]TestCatalogEntry)
+	for name, definition := range getTestDefinitions() {
+		result[name] = TestCatalogEntry{
+			Metadata:          definition.metadata,
+			UnavailableReason: a.getUnavailableReason(name, definition.metadata),
+		}
+	}
+	return result
+}
+
+func (a AvaTestSuite) getUnavailableReason(name string, metadata TestMetadata) string {
+	suiteImages := map[RequiredImage]string{
+		REQUIRED_IMAGE_NORMAL:    a.NormalImageName,
+		REQUIRED_IMAGE_BYZANTINE: a.ByzantineImageName,
+		REQUIRED_IMAGE_UPGRADE:   a.UpgradeImageName,
+	}
+	missingImages := []string{}
+	for _, requiredImage := range metadata.RequiredImages {
+		if suiteImages[requiredImage] == "" {
+			missingImages = append(missingImages, string(requiredImage))
+		}
+	}
+	if len(missingImages) > 0 {
+		return fmt.Sprintf("no %v image was given", strings.Join(missingImages, " or "))
+	}
+	if _, found := a.NetworkSpecs[name]; !found {
+		return fmt.Sprintf("no network spec named %v was given", name)
+	}
+	return ""
+}
+
+/*
+Selects the tests to run by name and tag. A test named explicitly must exist and be available; tests picked out by tag
+which are unavailable are skipped rather than being an error, and returned so that the caller can report them.
+
+Args:
+	testNames: The names of the tests to run, or empty to consider every test
+	includeTags: If non-empty, only tests with at least one of these tags are selected
+	excludeTags: Tests with any of these tags aren't selected, even if they're named or included by another tag
+
+Returns:
+	The names of the selected tests, which are all available
+	Test name -> reason the test was skipped, for each test that matched the selection but is unavailable
+*/
+func (a AvaTestSuite) SelectTests(
+	testNames map[string]bool,
+	includeTags map[string]bool,
+	excludeTags map[string]bool) (map[string]bool, map[string]string, error) {
+	validTags := map[string]bool{}
+	for _, tag := range GetAllTags() {
+		validTags[tag] = true
+	}
+	for _, tags := range []map[string]bool{includeTags, excludeTags} {
+		for tag := range tags {
+			if !validTags[tag] {
+				return nil, nil, stacktrace.NewError("Unrecognized tag '%v'; valid tags are %v", tag, GetAllTags())
+			}
+		}
+	}
+
+	catalog := a.GetTestCatalog()
+	candidateNames := []string{}
+	if len(testNames) > 0 {
+		for name := range testNames {
+			entry, found := catalog[name]
+			if !found {
+				return nil, nil, stacktrace.NewError("No test named '%v' exists", name)
+			}
+			if !entry.IsAvailable() {
+				return nil, nil, stacktrace.NewError("Test '%v' can't be run because %v", name, entry.UnavailableReason)
+			}
+			candidateNames = append(candidateNames, name)
+		}
+	} else {
+		for name := range catalog {
+			candidateNames = append(candidateNames, name)
+		}
+	}
+
+	selected := make(map[string]bool)
+	skipped := make(map[string]string)
+	for _, name := range candidateNames {
+		metadata := catalog[name].Metadata
+		if len(includeTags) > 0 && !metadata.hasAnyTag(includeTags) {
+			continue
+		}
+		if metadata.hasAnyTag(excludeTags) {
+			continue
+		}
+		if reason := catalog[name].UnavailableReason; reason != "" {
+			skipped[name] = reason
+			continue
+		}
+		selected[name] = true
+	}
+	return selected, skipped, nil
+}
@@ -0,0 +1,106 @@
+package ava_testsuite
+
+import (
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
+	"github.com/stretchr/testify/assert"
+)
+
+// The network specs checked in for the suite's tests, relative to this package
+const checkedInNetworkSpecsDirpath = "../../network_specs"
+
+var checkedInNetworkSpecs, checkedInNetworkSpecsErr = ava_networks.LoadNetworkSpecsDirectory(checkedInNetworkSpecsDirpath)
+
+var suiteWithoutByzantineImage = AvaTestSuite{
+	NormalImageName:  "gecko:latest",
+	UpgradeImageName: "gecko:next",
+	NetworkSpecs:     checkedInNetworkSpecs,
+}
+
+func TestEveryTestHasACheckedInNetworkSpec(t *testing.T) {
+	assert.NoError(t, checkedInNetworkSpecsErr)
+	suite := AvaTestSuite{
+		NormalImageName:    "gecko:latest",
+		ByzantineImageName: "gecko-byzantine:latest",
+		UpgradeImageName:   "gecko:next",
+		NetworkSpecs:       checkedInNetworkSpecs,
+	}
+	for name := range getTestDefinitions() {
+		spec, found := checkedInNetworkSpecs[name]
+		if assert.True(t, found, "Test %v has no network spec in %v", name, checkedInNetworkSpecsDirpath) {
+			_, err := spec.CreateLoader(suite.getImageVariables())
+			assert.NoError(t, err, "The network spec of test %v is invalid", name)
+		}
+	}
+}
+
+func TestTestsWithoutNetworkSpecsAreUnavailable(t *testing.T) {
+	suite := AvaTestSuite{
+		NormalImageName: "gecko:latest",
+		NetworkSpecs:    ava_networks.NetworkSpecs{},
+	}
+	catalog := suite.GetTestCatalog()
+	assert.Contains(t, catalog["stakingNetworkRpcWorkflowTest"].UnavailableReason, "network spec")
+	assert.Empty(t, suite.GetTests())
+}
+
+func TestUnavailableTestsLeftOutOfGetTests(t *testing.T) {
+	tests := suiteWithoutByzantineImage.GetTests()
+	catalog := suiteWithoutByzantineImage.GetTestCatalog()
+	assert.True(t, len(catalog) > len(tests))
+
+	for name, entry := range catalog {
+		_, found := tests[name]
+		assert.Equal(t, entry.IsAvailable(), found, "Test %v should be in GetTests iff it's available", name)
+		if entry.Metadata.hasAnyTag(map[string]bool{TAG_BYZANTINE: true}) {
+			assert.Contains(t, entry.UnavailableReason, "byzantine")
+		}
+	}
+}
+
+func TestEveryTestHasMetadata(t *testing.T) {
+	validTags := map[string]bool{}
+	for _, tag := range GetAllTags() {
+		validTags[tag] = true
+	}
+	for name, definition := range getTestDefinitions() {
+		assert.NotEmpty(t, definition.metadata.Description, "Test %v has no description", name)
+		assert.NotEmpty(t, definition.metadata.Tags, "Test %v has no tags", name)
+		for _, tag := range definition.metadata.Tags {
+			assert.True(t, validTags[tag], "Test %v has unrecognized tag %v", name, tag)
+		}
+	}
+}
+
+func TestSelectByTags(t *testing.T) {
+	selected, skipped, err := suiteWithoutByzantineImage.SelectTests(
+		map[string]bool{},
+		map[string]bool{TAG_SMOKE: true, TAG_BYZANTINE: true},
+		map[string]bool{TAG_SLOW: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"stakingNetworkRpcWorkflowTest": true}, selected)
+	assert.Contains(t, skipped, "stakingNetworkChitSpammerTest")
+	assert.Contains(t, skipped, "conflictingTxsVertexTest")
+}
+
+func TestSelectByNames(t *testing.T) {
+	selected, _, err := suiteWithoutByzantineImage.SelectTests(
+		map[string]bool{"stakingNetworkRpcWorkflowTest": true, "stakingNetworkFullyConnectedTest": true},
+		map[string]bool{},
+		map[string]bool{TAG_SLOW: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"stakingNetworkRpcWorkflowTest": true}, selected)
+}
+
+func TestSelectRejectsBadNamesAndTags(t *testing.T) {
+	_, _, err := suiteWithoutByzantineImage.SelectTests(map[string]bool{"noSuchTest": true}, map[string]bool{}, map[string]bool{})
+	assert.Error(t, err)
+
+	// A test named explicitly shouldn't be silently skipped
+	_, _, err = suiteWithoutByzantineImage.SelectTests(map[string]bool{"conflictingTxsVertexTest": true}, map[string]bool{}, map[string]bool{})
+	assert.Error(t, err)
+
+	_, _, err = suiteWithoutByzantineImage.SelectTests(map[string]bool{}, map[string]bool{"quick": true}, map[string]bool{})
+	assert.Error(t, err)
+}
@@ -1,11 +1,9 @@
 package unrequested_chit_spammer_test
 
 import (
-	"strconv"
 	"time"
 
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
-	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_services"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite/rpc_workflow_runner"
 	"github.com/kurtosis-tech/kurtosis/commons/networks"
 	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
@@ -14,27 +12,24 @@ import (
 )
 
 const (
-	normalNodeConfigId     networks.ConfigurationID = "normal-config"
-	byzantineConfigId      networks.ConfigurationID = "byzantine-config"
-	byzantineUsername                               = "byzantine_gecko"
-	byzantinePassword                               = "byzant1n3!"
-	stakerUsername                                  = "staker_gecko"
-	stakerPassword                                  = "test34test!23"
-	normalNodeServiceId    networks.ServiceID       = "normal-node"
-	byzantineNodePrefix    string                   = "byzantine-node-"
-	numberOfByzantineNodes                          = 4
-	seedAmount                                      = int64(50000000000000)
-	stakeAmount                                     = int64(30000000000000)
+	normalNodeConfigId  networks.ConfigurationID = "normal-config"
+	byzantineConfigId   networks.ConfigurationID = "byzantine-config"
+	byzantineUsername                            = "byzantine_gecko"
+	byzantinePassword                            = "byzant1n3!"
+	stakerUsername                               = "staker_gecko"
+	stakerPassword                               = "test34test!23"
+	normalNodeServiceId networks.ServiceID       = "normal-node"
+	seedAmount                                   = int64(50000000000000)
+	stakeAmount                                  = int64(30000000000000)
 
 	networkAcceptanceTimeoutRatio = 0.3
-	byzantineBehavior             = "byzantine-behavior"
-	chitSpammerBehavior           = "chit-spammer"
 )
 
 // ================ Byzantine Test - Spamming Unrequested Chit Messages ===================================
 type StakingNetworkUnrequestedChitSpammerTest struct {
-	ByzantineImageName string
-	NormalImageName    string
+	// The topology of the test's network, whose images can reference the variables in ImageVariables
+	NetworkSpec    ava_networks.NetworkSpec
+	ImageVariables map[string]string
 }
 
 func (test StakingNetworkUnrequestedChitSpammerTest) Run(network networks.Network, context testsuite.TestContext) {
@@ -42,8 +37,8 @@ func (test StakingNetworkUnrequestedChitSpammerTest) Run(network networks.Networ
 	networkAcceptanceTimeout := time.Duration(networkAcceptanceTimeoutRatio * float64(test.GetExecutionTimeout().Nanoseconds()))
 
 	// ============= ADD SET OF BYZANTINE NODES AS VALIDATORS ON THE NETWORK ===================
-	for i := 0; i < numberOfByzantineNodes; i++ {
-		byzClient, err := castedNetwork.GetGeckoClient(networks.ServiceID(byzantineNodePrefix + strconv.Itoa(i)))
+	for _, byzantineServiceId := range castedNetwork.GetServiceIdsWithConfig(byzantineConfigId) {
+		byzClient, err := castedNetwork.GetGeckoClient(byzantineServiceId)
 		if err != nil {
 			context.Fatal(stacktrace.Propagate(err, "Failed to get byzantine client."))
 		}
@@ -98,41 +93,7 @@ func (test StakingNetworkUnrequestedChitSpammerTest) Run(network networks.Networ
 }
 
 func (test StakingNetworkUnrequestedChitSpammerTest) GetNetworkLoader() (networks.NetworkLoader, error) {
-	// Define normal node and byzantine node configurations
-	serviceConfigs := map[networks.ConfigurationID]ava_networks.TestGeckoNetworkServiceConfig{
-		byzantineConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(true,
-			ava_services.LOG_LEVEL_DEBUG,
-			test.ByzantineImageName,
-			2,
-			2,
-			map[string]string{
-				"byzantine-behavior": "chit-spammer",
-			},
-		),
-		normalNodeConfigId: *ava_networks.NewTestGeckoNetworkServiceConfig(true,
-			ava_services.LOG_LEVEL_DEBUG,
-			test.NormalImageName,
-			6,
-			8,
-			make(map[string]string),
-		),
-	}
-	// Define the map from service->configuration for the network
-	serviceIdConfigMap := map[networks.ServiceID]networks.ConfigurationID{}
-	for i := 0; i < numberOfByzantineNodes; i++ {
-		serviceIdConfigMap[networks.ServiceID(byzantineNodePrefix+strconv.Itoa(i))] = byzantineConfigId
-	}
-	logrus.Debugf("Byzantine Image Name: %s", test.ByzantineImageName)
-	logrus.Debugf("Normal Image Name: %s", test.NormalImageName)
-
-	return ava_networks.NewTestGeckoNetworkLoader(
-		true,
-		test.NormalImageName,
-		ava_services.LOG_LEVEL_DEBUG,
-		2,
-		2,
-		serviceConfigs,
-		serviceIdConfigMap)
+	return test.NetworkSpec.CreateLoader(test.ImageVariables)
 }
 
 func (test StakingNetworkUnrequestedChitSpammerTest) GetExecutionTimeout() time.Duration {
@@ -140,7 +101,7 @@ func (test StakingNetworkUnrequestedChitSpammerTest) GetExecutionTimeout() time.
 }
 
 func (test StakingNetworkUnrequestedChitSpammerTest) GetSetupBuffer() time.Duration {
-	// TODO drop this when the availabilityChecker doesn't have a sleep, because we spin up a *bunch* of nodes before test
-	//  execution starts
+	// We spin up a *bunch* of nodes before test execution starts
+	// TODO drop this once the bootstrap check in the availability checker has been proven against the pinned Gecko image
 	return 12 * time.Minute
 }
@@ -1,6 +1,10 @@
 package verifier
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
 	"github.com/kurtosis-tech/kurtosis/commons/networks"
 	"github.com/palantir/stacktrace"
@@ -111,3 +115,108 @@ func (verifier NetworkStateVerifier) VerifyExpectedPeers(
 	}
 	return nil
 }
+
+/*
+Verifies that every node reports the version we expect it to be running, and that its peers see each other node at the
+version that node reports for itself
+
+Args:
+	expectedVersions: The mapping of service_id -> version (e.g. "avalanche/0.5.7") that each node should be running
+	allNodeIds: The mapping of service_id -> node_id
+	allGeckoClients: The mapping of service_id -> Gecko client for that service
+*/
+func (verifier NetworkStateVerifier) VerifyNodeVersions(
+		expectedVersions map[networks.ServiceID]string,
+		allNodeIds map[networks.ServiceID]string,
+		allGeckoClients map[networks.ServiceID]*gecko_client.GeckoClient) error {
+	expectedVersionsByNodeId := make(map[string]string)
+	for serviceId, expectedVersion := range expectedVersions {
+		client, found := allGeckoClients[serviceId]
+		if !found {
+			return stacktrace.NewError("An expected version was given for service ID %v, but there's no Gecko client for it", serviceId)
+		}
+		nodeId, found := allNodeIds[serviceId]
+		if !found {
+			return stacktrace.NewError("An expected version was given for service ID %v, but there's no node ID for it", serviceId)
+		}
+		actualVersion, err := client.InfoApi().GetNodeVersion()
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get node version of service with ID %v", serviceId)
+		}
+		if actualVersion != expectedVersion {
+			return stacktrace.NewError(
+				"Service ID %v reports version %v, but expected version %v",
+				serviceId,
+				actualVersion,
+				expectedVersion)
+		}
+		expectedVersionsByNodeId[nodeId] = expectedVersion
+	}
+
+	for serviceId, client := range allGeckoClients {
+		peers, err := client.InfoApi().GetPeers()
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get peers from service with ID %v", serviceId)
+		}
+		for _, peer := range peers {
+			expectedVersion, found := expectedVersionsByNodeId[peer.Id]
+			if !found {
+				continue
+			}
+			if peer.Version != expectedVersion {
+				return stacktrace.NewError(
+					"Service ID %v sees peer with node ID %v at version %v, but expected version %v",
+					serviceId,
+					peer.Id,
+					peer.Version,
+					expectedVersion)
+			}
+		}
+	}
+	return nil
+}
+
+/*
+Verifies that every node reports itself healthy, returning an error that lists every failing check on every service if
+any node isn't
+
+Args:
+	allGeckoClients: The mapping of service_id -> Gecko client for the services to check
+*/
+func (verifier NetworkStateVerifier) VerifyNetworkHealthy(allGeckoClients map[networks.ServiceID]*gecko_client.GeckoClient) error {
+	// Sorted so that the failure report is stable across runs
+	serviceIds := make([]string, 0, len(allGeckoClients))
+	for serviceId := range allGeckoClients {
+		serviceIds = append(serviceIds, string(serviceId))
+	}
+	sort.Strings(serviceIds)
+
+	failures := make([]string, 0)
+	for _, serviceIdStr := range serviceIds {
+		serviceId := networks.ServiceID(serviceIdStr)
+		livenessInfo, err := allGeckoClients[serviceId].HealthApi().GetLiveness()
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to get liveness of service with ID %v", serviceId)
+		}
+		failingChecks := livenessInfo.GetFailingChecks()
+		for _, checkName := range failingChecks {
+			check := livenessInfo.Checks[checkName]
+			failures = append(failures, fmt.Sprintf(
+				"service %v: check %v has failed %v times in a row since %v (message: %v, error: %v)",
+				serviceId,
+				checkName,
+				check.ContiguousFailures,
+				check.TimeOfFirstFailure,
+				check.Message,
+				check.Error))
+		}
+		// A node can report itself unhealthy without any individual check failing, so this must be checked separately
+		if !livenessInfo.Healthy && len(failingChecks) == 0 {
+			failures = append(failures, fmt.Sprintf("service %v: reports itself unhealthy, though no checks are failing", serviceId))
+		}
+	}
+	if len(failures) > 0 {
+		return stacktrace.NewError("Not all nodes in the network are healthy:\n%v", strings.Join(failures, "\n"))
+	}
+	return nil
+}
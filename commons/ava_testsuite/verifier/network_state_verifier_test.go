@@ -0,0 +1,43 @@
+package verifier
+
+import (
+	"testing"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client"
+	"github.com/kurtosis-tech/ava-e2e-tests/gecko_client/fake_gecko_node"
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyNodeVersionsWithoutClient(t *testing.T) {
+	verifier := NetworkStateVerifier{}
+	expectedVersions := map[networks.ServiceID]string{
+		"node-without-client": "avalanche/0.5.7",
+	}
+	allNodeIds := map[networks.ServiceID]string{
+		"node-without-client": "node-id",
+	}
+	err := verifier.VerifyNodeVersions(expectedVersions, allNodeIds, map[networks.ServiceID]*gecko_client.GeckoClient{})
+	assert.Error(t, err)
+}
+
+func TestVerifyNetworkHealthyReportsFailingChecks(t *testing.T) {
+	healthyNode := fake_gecko_node.NewFakeGeckoNode("healthy-node-id", map[string]int64{})
+	defer healthyNode.Close()
+	unhealthyNode := fake_gecko_node.NewFakeGeckoNode("unhealthy-node-id", map[string]int64{})
+	defer unhealthyNode.Close()
+	allGeckoClients := map[networks.ServiceID]*gecko_client.GeckoClient{
+		"healthy-node":   gecko_client.NewGeckoClient(healthyNode.GetIpAddr(), healthyNode.GetPort()),
+		"unhealthy-node": gecko_client.NewGeckoClient(unhealthyNode.GetIpAddr(), unhealthyNode.GetPort()),
+	}
+	verifier := NetworkStateVerifier{}
+
+	assert.NoError(t, verifier.VerifyNetworkHealthy(allGeckoClients))
+
+	unhealthyNode.FailHealthCheck("network.validators.heartbeat", "no heartbeat")
+	err := verifier.VerifyNetworkHealthy(allGeckoClients)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "service unhealthy-node: check network.validators.heartbeat has failed 1 times in a row")
+		assert.NotContains(t, err.Error(), "service healthy-node:")
+	}
+}
@@ -0,0 +1,11 @@
+package string_utils
+
+// Returns true if the given values contain the target string, e.g. when checking the users or addresses a node lists
+func Contains(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
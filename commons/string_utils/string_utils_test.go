@@ -0,0 +1,14 @@
+package string_utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContains(t *testing.T) {
+	values := []string{"user1", "user2"}
+	assert.True(t, Contains(values, "user2"))
+	assert.False(t, Contains(values, "user3"))
+	assert.False(t, Contains([]string{}, "user1"))
+}
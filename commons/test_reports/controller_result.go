@@ -0,0 +1,136 @@
+package test_reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	dockerBinary = "docker"
+
+	// Where a result volume is mounted in the helper containers that write and read it
+	resultVolumeMountpoint = "/test-result"
+
+	// A small image with sh and cat, pinned so that every run writes and reads result volumes with the same image
+	resultVolumeHelperImage = "alpine:3.12"
+
+	controllerResultFilename = "controller-result.json"
+)
+
+/*
+What the test controller saw of the one test it ran, which the initializer can't otherwise know because the test suite
+runner only reports whether the test passed
+*/
+type ControllerResult struct {
+	// Why the test's network couldn't be set up, in which case the test never executed
+	SetupError string `json:"setupError,omitempty"`
+
+	// Why the test failed while executing
+	TestError string `json:"testError,omitempty"`
+
+	NetworkSetupDurationSeconds float64 `json:"networkSetupDurationSeconds"`
+
+	// Zero if the test never started executing
+	ExecutionDurationSeconds float64 `json:"executionDurationSeconds"`
+}
+
+// Fills in the parts of the test result that only the controller knows
+func (result ControllerResult) ApplyTo(testResult *TestResult) {
+	testResult.SetupError = result.SetupError
+	testResult.TestError = result.TestError
+	testResult.NetworkSetupDurationSeconds = result.NetworkSetupDurationSeconds
+	testResult.ExecutionDurationSeconds = result.ExecutionDurationSeconds
+}
+
+/*
+Passes a controller's result to the initializer through a Docker volume which the initializer creates for the test. The
+test suite runner only mounts the test volume on the controller, and doesn't tell the initializer its name, so the result
+volume is written and read through short-lived helper containers instead of being mounted.
+
+NOTE: This needs the Docker CLI and access to the Docker engine, which both the initializer and controller have; the
+helper containers run a small Alpine image, which Docker pulls the first time it's used if it's not on the engine.
+*/
+type ResultVolume struct {
+	volumeName string
+}
+
+/*
+Args:
+	volumeName: The name of the Docker volume to pass the result through
+*/
+func NewResultVolume(volumeName string) *ResultVolume {
+	return &ResultVolume{
+		volumeName: volumeName,
+	}
+}
+
+func (volume ResultVolume) Create() error {
+	if _, err := runDockerCommand(nil, "volume", "create", volume.volumeName); err != nil {
+		return stacktrace.Propagate(err, "Could not create result volume %v", volume.volumeName)
+	}
+	return nil
+}
+
+func (volume ResultVolume) Remove() error {
+	if _, err := runDockerCommand(nil, "volume", "rm", "--force", volume.volumeName); err != nil {
+		return stacktrace.Propagate(err, "Could not remove result volume %v", volume.volumeName)
+	}
+	return nil
+}
+
+func (volume ResultVolume) WriteControllerResult(result ControllerResult) error {
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not serialize the controller result to JSON")
+	}
+	writeCommand := fmt.Sprintf("cat > %v", path.Join(resultVolumeMountpoint, controllerResultFilename))
+	if _, err := runDockerCommand(bytes.NewReader(resultBytes), volume.getHelperArgs("sh", "-c", writeCommand)...); err != nil {
+		return stacktrace.Propagate(err, "Could not write the controller result to volume %v", volume.volumeName)
+	}
+	return nil
+}
+
+func (volume ResultVolume) ReadControllerResult() (*ControllerResult, error) {
+	resultBytes, err := runDockerCommand(nil, volume.getHelperArgs("cat", path.Join(resultVolumeMountpoint, controllerResultFilename))...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not read the controller result from volume %v; the controller may not have run", volume.volumeName)
+	}
+	var result ControllerResult
+	if err := json.Unmarshal(resultBytes, &result); err != nil {
+		return nil, stacktrace.Propagate(err, "Could not parse the controller result read from volume %v", volume.volumeName)
+	}
+	return &result, nil
+}
+
+// Gets the Docker args to run the given command in a helper container with the volume mounted
+func (volume ResultVolume) getHelperArgs(entrypoint string, args ...string) []string {
+	result := []string{
+		"run",
+		"--rm",
+		"--interactive",
+		"--volume", fmt.Sprintf("%v:%v", volume.volumeName, resultVolumeMountpoint),
+		"--entrypoint", entrypoint,
+		resultVolumeHelperImage,
+	}
+	return append(result, args...)
+}
+
+// Runs the Docker CLI, returning its stdout
+func runDockerCommand(stdin io.Reader, args ...string) ([]byte, error) {
+	command := exec.Command(dockerBinary, args...)
+	command.Stdin = stdin
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		return nil, stacktrace.Propagate(err, "Docker command with args %v failed with output: %v", args, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
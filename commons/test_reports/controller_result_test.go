@@ -0,0 +1,39 @@
+package test_reports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControllerResultAppliedToTestResult(t *testing.T) {
+	testResult := TestResult{Name: "failingTest", Status: TEST_STATUS_FAILED, DurationSeconds: 100}
+	ControllerResult{
+		TestError:                   "Balance mismatch",
+		NetworkSetupDurationSeconds: 40,
+		ExecutionDurationSeconds:    50,
+	}.ApplyTo(&testResult)
+
+	assert.Equal(t, "Balance mismatch", testResult.TestError)
+	assert.Empty(t, testResult.SetupError)
+	assert.Equal(t, 40.0, testResult.NetworkSetupDurationSeconds)
+	assert.Equal(t, 50.0, testResult.ExecutionDurationSeconds)
+	assert.Equal(t, 100.0, testResult.DurationSeconds, "The initializer's own measurements shouldn't be touched")
+}
+
+func TestResultVolumeHelperArgs(t *testing.T) {
+	volume := NewResultVolume("result-volume")
+	args := volume.getHelperArgs("cat", "/test-result/controller-result.json")
+	assert.Equal(
+		t,
+		[]string{
+			"run",
+			"--rm",
+			"--interactive",
+			"--volume", "result-volume:/test-result",
+			"--entrypoint", "cat",
+			"alpine:3.12",
+			"/test-result/controller-result.json",
+		},
+		args)
+}
@@ -0,0 +1,149 @@
+package test_reports
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+// The results of one iteration of running the same tests repeatedly
+type TestRunIteration struct {
+	// 1-indexed
+	Iteration int          `json:"iteration"`
+	CertSeed  int64        `json:"certSeed"`
+	Results   []TestResult `json:"results"`
+}
+
+func (iteration TestRunIteration) AllTestsPassed() bool {
+	for _, result := range iteration.Results {
+		if result.Status != TEST_STATUS_PASSED {
+			return false
+		}
+	}
+	return true
+}
+
+// A run of a test that didn't pass, with what's needed to reproduce it
+type FailedTestRun struct {
+	Iteration       int        `json:"iteration"`
+	CertSeed        int64      `json:"certSeed"`
+	Status          TestStatus `json:"status"`
+	Error           string     `json:"error,omitempty"`
+	SetupError      string     `json:"setupError,omitempty"`
+	TestError       string     `json:"testError,omitempty"`
+	StartTime       time.Time  `json:"startTime"`
+	DurationSeconds float64    `json:"durationSeconds"`
+}
+
+// How reliably a test passed across the iterations of a repeated run
+type TestFlakiness struct {
+	Name                string          `json:"name"`
+	Runs                int             `json:"runs"`
+	Passes              int             `json:"passes"`
+	PassRate            float64         `json:"passRate"`
+	MeanDurationSeconds float64         `json:"meanDurationSeconds"`
+	FailedRuns          []FailedTestRun `json:"failedRuns"`
+}
+
+type FlakinessReport struct {
+	StartTime       time.Time `json:"startTime"`
+	DurationSeconds float64   `json:"durationSeconds"`
+
+	GeckoImageName     string `json:"geckoImageName"`
+	ByzantineImageName string `json:"byzantineImageName,omitempty"`
+	UpgradeImageName   string `json:"upgradeImageName,omitempty"`
+
+	IterationsRequested int `json:"iterationsRequested"`
+	IterationsRun       int `json:"iterationsRun"`
+
+	// Per-test summaries, sorted by test name
+	Tests []TestFlakiness `json:"tests"`
+
+	Iterations []TestRunIteration `json:"iterations"`
+}
+
+/*
+Summarizes the iterations of a repeated run into a report
+
+Args:
+	iterationsRequested: The number of iterations that were asked for, which is more than were run if the run stopped
+		at the first failure
+	iterations: The iterations that were run
+*/
+func NewFlakinessReport(iterationsRequested int, iterations []TestRunIteration) FlakinessReport {
+	flakinessByName := map[string]*TestFlakiness{}
+	totalDurationsByName := map[string]float64{}
+	for _, iteration := range iterations {
+		for _, result := range iteration.Results {
+			flakiness, found := flakinessByName[result.Name]
+			if !found {
+				flakiness = &TestFlakiness{
+					Name:       result.Name,
+					FailedRuns: []FailedTestRun{},
+				}
+				flakinessByName[result.Name] = flakiness
+			}
+			flakiness.Runs++
+			totalDurationsByName[result.Name] += result.DurationSeconds
+			if result.Status == TEST_STATUS_PASSED {
+				flakiness.Passes++
+				continue
+			}
+			flakiness.FailedRuns = append(flakiness.FailedRuns, FailedTestRun{
+				Iteration:       iteration.Iteration,
+				CertSeed:        iteration.CertSeed,
+				Status:          result.Status,
+				Error:           result.Error,
+				SetupError:      result.SetupError,
+				TestError:       result.TestError,
+				StartTime:       result.StartTime,
+				DurationSeconds: result.DurationSeconds,
+			})
+		}
+	}
+
+	tests := make([]TestFlakiness, 0, len(flakinessByName))
+	for name, flakiness := range flakinessByName {
+		flakiness.PassRate = float64(flakiness.Passes) / float64(flakiness.Runs)
+		flakiness.MeanDurationSeconds = totalDurationsByName[name] / float64(flakiness.Runs)
+		tests = append(tests, *flakiness)
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Name < tests[j].Name
+	})
+
+	return FlakinessReport{
+		IterationsRequested: iterationsRequested,
+		IterationsRun:       len(iterations),
+		Tests:               tests,
+		Iterations:          iterations,
+	}
+}
+
+func (report FlakinessReport) AllRunsPassed() bool {
+	for _, test := range report.Tests {
+		if test.Passes != test.Runs {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Writes the report as JSON, creating the file's directory if necessary
+
+Args:
+	reportFilepath: The file to write the report to, which will be overwritten if it exists
+*/
+func (report FlakinessReport) WriteJson(reportFilepath string) error {
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not serialize the flakiness report to JSON")
+	}
+	if err := writeReportFile(reportFilepath, reportBytes); err != nil {
+		return stacktrace.Propagate(err, "Could not write JSON flakiness report")
+	}
+	return nil
+}
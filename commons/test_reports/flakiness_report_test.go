@@ -0,0 +1,80 @@
+package test_reports
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func getTestIterations() []TestRunIteration {
+	return []TestRunIteration{
+		{
+			Iteration: 1,
+			CertSeed:  11,
+			Results: []TestResult{
+				{Name: "stableTest", Status: TEST_STATUS_PASSED, DurationSeconds: 10},
+				{Name: "flakyTest", Status: TEST_STATUS_PASSED, DurationSeconds: 20},
+			},
+		},
+		{
+			Iteration: 2,
+			CertSeed:  22,
+			Results: []TestResult{
+				{Name: "stableTest", Status: TEST_STATUS_PASSED, DurationSeconds: 30},
+				{Name: "flakyTest", Status: TEST_STATUS_FAILED, DurationSeconds: 40},
+			},
+		},
+	}
+}
+
+func TestFlakinessReportAggregatesPassRates(t *testing.T) {
+	report := NewFlakinessReport(5, getTestIterations())
+	assert.Equal(t, 5, report.IterationsRequested)
+	assert.Equal(t, 2, report.IterationsRun)
+	assert.False(t, report.AllRunsPassed())
+
+	if assert.Len(t, report.Tests, 2) {
+		flakyTest := report.Tests[0]
+		assert.Equal(t, "flakyTest", flakyTest.Name)
+		assert.Equal(t, 2, flakyTest.Runs)
+		assert.Equal(t, 1, flakyTest.Passes)
+		assert.Equal(t, 0.5, flakyTest.PassRate)
+		assert.Equal(t, 30.0, flakyTest.MeanDurationSeconds)
+		expectedFailedRuns := []FailedTestRun{
+			{Iteration: 2, CertSeed: 22, Status: TEST_STATUS_FAILED, DurationSeconds: 40},
+		}
+		assert.Equal(t, expectedFailedRuns, flakyTest.FailedRuns)
+
+		stableTest := report.Tests[1]
+		assert.Equal(t, "stableTest", stableTest.Name)
+		assert.Equal(t, 1.0, stableTest.PassRate)
+		assert.Empty(t, stableTest.FailedRuns)
+	}
+}
+
+func TestIterationPassesOnlyIfAllTestsPass(t *testing.T) {
+	iterations := getTestIterations()
+	assert.True(t, iterations[0].AllTestsPassed())
+	assert.False(t, iterations[1].AllTestsPassed())
+	assert.True(t, NewFlakinessReport(1, iterations[:1]).AllRunsPassed())
+}
+
+func TestFlakinessReportWritesJson(t *testing.T) {
+	tempDirpath, err := ioutil.TempDir("", "flakiness-reports")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDirpath)
+
+	reportFilepath := filepath.Join(tempDirpath, "flakiness.json")
+	assert.NoError(t, NewFlakinessReport(2, getTestIterations()).WriteJson(reportFilepath))
+
+	reportBytes, err := ioutil.ReadFile(reportFilepath)
+	assert.NoError(t, err)
+	var parsedReport FlakinessReport
+	assert.NoError(t, json.Unmarshal(reportBytes, &parsedReport))
+	assert.Len(t, parsedReport.Tests, 2)
+	assert.Len(t, parsedReport.Iterations, 2)
+}
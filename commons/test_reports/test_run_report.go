@@ -0,0 +1,220 @@
+package test_reports
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Name of the suite in JUnit reports, which CI systems group the test cases under
+	junitSuiteName = "ava-e2e-tests"
+
+	reportFilePerms = 0644
+	reportDirPerms  = 0755
+)
+
+// ========= Test Status Enum ========================
+type TestStatus string
+
+const (
+	// The test ran and passed
+	TEST_STATUS_PASSED TestStatus = "passed"
+
+	// The test ran and failed, either while setting up its network or while executing
+	TEST_STATUS_FAILED TestStatus = "failed"
+
+	// The test couldn't be run at all (e.g. the controller container couldn't be started)
+	TEST_STATUS_ERRORED TestStatus = "errored"
+)
+
+// ========= Report ========================
+type TestResult struct {
+	Name   string     `json:"name"`
+	Status TestStatus `json:"status"`
+
+	// Why the test couldn't be run; only set when the status is errored
+	Error string `json:"error,omitempty"`
+
+	// Why the test's network couldn't be set up, as reported by the controller
+	SetupError string `json:"setupError,omitempty"`
+
+	// Why the test failed while executing, as reported by the controller
+	TestError string `json:"testError,omitempty"`
+
+	StartTime time.Time `json:"startTime"`
+
+	// Wall-clock time taken to run the test, including starting the controller and setting up and tearing down its network
+	DurationSeconds float64 `json:"durationSeconds"`
+
+	// As measured by the controller; both are zero if the controller didn't report them, and execution is zero if the
+	//  test never started executing
+	NetworkSetupDurationSeconds float64 `json:"networkSetupDurationSeconds"`
+	ExecutionDurationSeconds    float64 `json:"executionDurationSeconds"`
+}
+
+// The outcome of a single run of the initializer, along with everything needed to reproduce it
+type TestRunReport struct {
+	StartTime       time.Time `json:"startTime"`
+	DurationSeconds float64   `json:"durationSeconds"`
+
+	GeckoImageName     string `json:"geckoImageName"`
+	ByzantineImageName string `json:"byzantineImageName,omitempty"`
+	UpgradeImageName   string `json:"upgradeImageName,omitempty"`
+	CertSeed           int64  `json:"certSeed"`
+
+	Results []TestResult `json:"results"`
+}
+
+func (report TestRunReport) AllTestsPassed() bool {
+	for _, result := range report.Results {
+		if result.Status != TEST_STATUS_PASSED {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+Writes the report as JSON, creating the file's directory if necessary
+
+Args:
+	reportFilepath: The file to write the report to, which will be overwritten if it exists
+*/
+func (report TestRunReport) WriteJson(reportFilepath string) error {
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not serialize the test run report to JSON")
+	}
+	if err := writeReportFile(reportFilepath, reportBytes); err != nil {
+		return stacktrace.Propagate(err, "Could not write JSON test run report")
+	}
+	return nil
+}
+
+/*
+Writes the report as JUnit XML, with one test case per test, creating the file's directory if necessary
+
+Args:
+	reportFilepath: The file to write the report to, which will be overwritten if it exists
+*/
+func (report TestRunReport) WriteJUnit(reportFilepath string) error {
+	reportBytes, err := xml.MarshalIndent(report.toJUnit(), "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not serialize the test run report to JUnit XML")
+	}
+	reportBytes = append([]byte(xml.Header), reportBytes...)
+	if err := writeReportFile(reportFilepath, reportBytes); err != nil {
+		return stacktrace.Propagate(err, "Could not write JUnit test run report")
+	}
+	return nil
+}
+
+func writeReportFile(reportFilepath string, contents []byte) error {
+	if err := os.MkdirAll(filepath.Dir(reportFilepath), reportDirPerms); err != nil {
+		return stacktrace.Propagate(err, "Could not create the directory of report file %v", reportFilepath)
+	}
+	if err := ioutil.WriteFile(reportFilepath, contents, reportFilePerms); err != nil {
+		return stacktrace.Propagate(err, "Could not write report file %v", reportFilepath)
+	}
+	return nil
+}
+
+// ========= JUnit Serialization ========================
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+func (report TestRunReport) toJUnit() junitTestSuites {
+	suite := junitTestSuite{
+		Name:      junitSuiteName,
+		Tests:     len(report.Results),
+		Time:      formatJUnitSeconds(report.DurationSeconds),
+		Timestamp: report.StartTime.UTC().Format(time.RFC3339),
+		Properties: []junitProperty{
+			{Name: "geckoImageName", Value: report.GeckoImageName},
+			{Name: "byzantineImageName", Value: report.ByzantineImageName},
+			{Name: "upgradeImageName", Value: report.UpgradeImageName},
+			{Name: "certSeed", Value: fmt.Sprintf("%d", report.CertSeed)},
+		},
+		TestCases: []junitTestCase{},
+	}
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: junitSuiteName,
+			Time:      formatJUnitSeconds(result.DurationSeconds),
+		}
+		switch result.Status {
+		case TEST_STATUS_FAILED:
+			suite.Failures++
+			testCase.Failure = getJUnitFailure(result)
+		case TEST_STATUS_ERRORED:
+			suite.Errors++
+			testCase.Error = &junitMessage{
+				Message: "Test could not be run",
+				Body:    result.Error,
+			}
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	return junitTestSuites{Suites: []junitTestSuite{suite}}
+}
+
+func getJUnitFailure(result TestResult) *junitMessage {
+	if result.SetupError != "" {
+		return &junitMessage{
+			Message: "Test network could not be set up, so the test never executed",
+			Body:    result.SetupError,
+		}
+	}
+	if result.TestError != "" {
+		return &junitMessage{
+			Message: "Test failed while executing",
+			Body:    result.TestError,
+		}
+	}
+	return &junitMessage{
+		Message: "Test failed; the controller didn't report whether it failed during network setup or execution, so see its logs",
+	}
+}
+
+func formatJUnitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
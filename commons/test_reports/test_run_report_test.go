@@ -0,0 +1,95 @@
+package test_reports
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testStartTime = time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+
+func getTestReport() TestRunReport {
+	return TestRunReport{
+		StartTime:       testStartTime,
+		DurationSeconds: 300,
+		GeckoImageName:  "gecko:latest",
+		CertSeed:        1234,
+		Results: []TestResult{
+			{Name: "passingTest", Status: TEST_STATUS_PASSED, StartTime: testStartTime, DurationSeconds: 100},
+			{Name: "failingTest", Status: TEST_STATUS_FAILED, TestError: "Balance mismatch", StartTime: testStartTime, DurationSeconds: 150.5, NetworkSetupDurationSeconds: 50, ExecutionDurationSeconds: 90},
+			{Name: "setupFailingTest", Status: TEST_STATUS_FAILED, SetupError: "Boot node never came up", StartTime: testStartTime, DurationSeconds: 60, NetworkSetupDurationSeconds: 55},
+			{Name: "erroredTest", Status: TEST_STATUS_ERRORED, Error: "Could not start controller", StartTime: testStartTime, DurationSeconds: 1},
+		},
+	}
+}
+
+func TestAllTestsPassed(t *testing.T) {
+	assert.False(t, getTestReport().AllTestsPassed())
+
+	passingReport := TestRunReport{
+		Results: []TestResult{{Name: "passingTest", Status: TEST_STATUS_PASSED}},
+	}
+	assert.True(t, passingReport.AllTestsPassed())
+}
+
+func TestJsonReportRoundTrips(t *testing.T) {
+	tempDirpath, err := ioutil.TempDir("", "test-reports")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDirpath)
+
+	// The report's directory doesn't exist yet, and should be created
+	reportFilepath := filepath.Join(tempDirpath, "reports", "report.json")
+	report := getTestReport()
+	assert.NoError(t, report.WriteJson(reportFilepath))
+
+	reportBytes, err := ioutil.ReadFile(reportFilepath)
+	assert.NoError(t, err)
+	var parsedReport TestRunReport
+	assert.NoError(t, json.Unmarshal(reportBytes, &parsedReport))
+	assert.Equal(t, report, parsedReport)
+}
+
+func TestJUnitReportCountsFailuresAndErrors(t *testing.T) {
+	tempDirpath, err := ioutil.TempDir("", "test-reports")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDirpath)
+
+	reportFilepath := filepath.Join(tempDirpath, "report.xml")
+	assert.NoError(t, getTestReport().WriteJUnit(reportFilepath))
+
+	reportBytes, err := ioutil.ReadFile(reportFilepath)
+	assert.NoError(t, err)
+	var parsedReport junitTestSuites
+	assert.NoError(t, xml.Unmarshal(reportBytes, &parsedReport))
+
+	if assert.Len(t, parsedReport.Suites, 1) {
+		suite := parsedReport.Suites[0]
+		assert.Equal(t, 4, suite.Tests)
+		assert.Equal(t, 2, suite.Failures)
+		assert.Equal(t, 1, suite.Errors)
+		assert.Equal(t, "300.000", suite.Time)
+		assert.Contains(t, suite.Properties, junitProperty{Name: "certSeed", Value: "1234"})
+
+		if assert.Len(t, suite.TestCases, 4) {
+			assert.Nil(t, suite.TestCases[0].Failure)
+			assert.Nil(t, suite.TestCases[0].Error)
+			assert.Equal(t, "150.500", suite.TestCases[1].Time)
+			if assert.NotNil(t, suite.TestCases[1].Failure) {
+				assert.Equal(t, "Balance mismatch", suite.TestCases[1].Failure.Body)
+			}
+			if assert.NotNil(t, suite.TestCases[2].Failure) {
+				assert.Contains(t, suite.TestCases[2].Failure.Message, "set up")
+				assert.Equal(t, "Boot node never came up", suite.TestCases[2].Failure.Body)
+			}
+			if assert.NotNil(t, suite.TestCases[3].Error) {
+				assert.Equal(t, "Could not start controller", suite.TestCases[3].Error.Body)
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"time"
+
+	"github.com/kurtosis-tech/kurtosis/commons/networks"
+	"github.com/kurtosis-tech/kurtosis/commons/testsuite"
+)
+
+/*
+Wraps a test suite so that the time at which a test starts executing, once its network has been set up, is recorded,
+which lets the controller report how long network setup and execution each took
+*/
+type executionTimingTestSuite struct {
+	testsuite.TestSuite
+
+	// Set when a test starts executing; zero if no test has
+	executionStartTime *time.Time
+}
+
+func newExecutionTimingTestSuite(testSuite testsuite.TestSuite) executionTimingTestSuite {
+	return executionTimingTestSuite{
+		TestSuite:          testSuite,
+		executionStartTime: &time.Time{},
+	}
+}
+
+func (suite executionTimingTestSuite) GetTests() map[string]testsuite.Test {
+	result := make(map[string]testsuite.Test)
+	for name, test := range suite.TestSuite.GetTests() {
+		result[name] = executionTimingTest{Test: test, executionStartTime: suite.executionStartTime}
+	}
+	return result
+}
+
+type executionTimingTest struct {
+	testsuite.Test
+	executionStartTime *time.Time
+}
+
+func (test executionTimingTest) Run(network networks.Network, context testsuite.TestContext) {
+	*test.executionStartTime = time.Now()
+	test.Test.Run(network, context)
+}
@@ -4,13 +4,26 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path"
+	"time"
 
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/logging"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/test_reports"
 	"github.com/kurtosis-tech/kurtosis/controller"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// Name of the directory on the test volume where JSON RPC recordings will be written
+	rpcRecordingsDirname = "rpc-recordings"
+
+	// Distinct so that a setup failure can be told apart from a test failure by the exit code alone
+	testErrorExitCode  = 1
+	setupErrorExitCode = 2
+)
+
 func main() {
 	// NOTE: we'll want to chnage the ForceColors to false if we ever want structured logging
 	logrus.SetFormatter(&logrus.TextFormatter{
@@ -48,6 +61,18 @@ func main() {
 		"The name of a pre-built byzantine Gecko image, either on the local Docker engine or in Docker Hub",
 	)
 
+	upgradeImageNameArg := flag.String(
+		"upgrade-image-name",
+		"",
+		"Name of Docker image of the Gecko version to upgrade to in the rolling upgrade test",
+	)
+
+	networkSpecsArg := flag.String(
+		"network-specs",
+		"",
+		"JSON object of the network spec of each test, keyed by test name, as loaded by the initializer from its network specs directory",
+	)
+
 	dockerNetworkArg := flag.String(
 		"docker-network",
 		"",
@@ -72,6 +97,18 @@ func main() {
 		"IP address of the gateway address on the Docker network that the test controller is running in",
 	)
 
+	certSeedArg := flag.Int64(
+		"cert-seed",
+		0,
+		"Seed to generate the test nodes' certs from (0 to generate them randomly)",
+	)
+
+	testResultVolumeArg := flag.String(
+		"test-result-volume",
+		"",
+		"Name of a Docker volume created by the initializer to write the test's setup and test errors and durations to (empty: don't write them)",
+	)
+
 	logLevelArg := flag.String(
 		"log-level",
 		"info",
@@ -97,10 +134,23 @@ func main() {
 		*geckoImageNameArg)
 
 	logrus.Debugf("Byzantine image name: %s", *byzantineImageNameArg)
+	logrus.Debugf("Upgrade image name: %s", *upgradeImageNameArg)
+	logrus.Infof("Cert seed: %v", *certSeedArg)
+	networkSpecs, err := ava_networks.ParseNetworkSpecs([]byte(*networkSpecsArg))
+	if err != nil {
+		logrus.Error("An error occurred parsing the network specs passed by the initializer:")
+		logrus.Error(err)
+		os.Exit(1)
+	}
 	testSuite := ava_testsuite.AvaTestSuite{
-		ByzantineImageName: *byzantineImageNameArg,
-		NormalImageName:    *geckoImageNameArg,
+		ByzantineImageName:  *byzantineImageNameArg,
+		NormalImageName:     *geckoImageNameArg,
+		UpgradeImageName:    *upgradeImageNameArg,
+		NetworkSpecs:        networkSpecs,
+		RpcRecordingDirpath: path.Join(*testVolumeMountpointArg, rpcRecordingsDirname),
+		CertSeed:            *certSeedArg,
 	}
+	timedTestSuite := newExecutionTimingTestSuite(testSuite)
 	controller := controller.NewTestController(
 		*testVolumeArg,
 		*testVolumeMountpointArg,
@@ -108,20 +158,49 @@ func main() {
 		*subnetMaskArg,
 		*gatewayIpArg,
 		*testControllerIpArg,
-		testSuite,
+		timedTestSuite,
 		*testNameArg)
 
 	logrus.Infof("Running test '%v'...", *testNameArg)
+	startTime := time.Now()
 	setupErr, testErr := controller.RunTest()
+	result := test_reports.ControllerResult{}
+	if timedTestSuite.executionStartTime.IsZero() {
+		result.NetworkSetupDurationSeconds = time.Since(startTime).Seconds()
+		logrus.Infof("Test %v spent %v setting up its network and never started executing", *testNameArg, time.Since(startTime))
+	} else {
+		result.NetworkSetupDurationSeconds = timedTestSuite.executionStartTime.Sub(startTime).Seconds()
+		result.ExecutionDurationSeconds = time.Since(*timedTestSuite.executionStartTime).Seconds()
+		logrus.Infof(
+			"Test %v spent %v setting up its network and %v executing and tearing it down",
+			*testNameArg,
+			timedTestSuite.executionStartTime.Sub(startTime),
+			time.Since(*timedTestSuite.executionStartTime))
+	}
+	if setupErr != nil {
+		result.SetupError = setupErr.Error()
+	}
+	if testErr != nil {
+		result.TestError = testErr.Error()
+	}
+	if *testResultVolumeArg != "" {
+		// Not being able to report the result shouldn't change the test's outcome, so this is only logged
+		resultVolume := test_reports.NewResultVolume(*testResultVolumeArg)
+		if err := resultVolume.WriteControllerResult(result); err != nil {
+			logrus.Errorf("Could not write the result of test %v for the initializer:", *testNameArg)
+			fmt.Fprintln(logrus.StandardLogger().Out, err)
+		}
+	}
+
 	if setupErr != nil {
 		logrus.Errorf("Test %v encountered an error during setup (test did not run):", *testNameArg)
 		fmt.Fprintln(logrus.StandardLogger().Out, setupErr)
-		os.Exit(1)
+		os.Exit(setupErrorExitCode)
 	}
 	if testErr != nil {
 		logrus.Errorf("Test %v failed:", *testNameArg)
 		fmt.Fprintln(logrus.StandardLogger().Out, testErr)
-		os.Exit(1)
+		os.Exit(testErrorExitCode)
 	}
 	logrus.Infof("Test %v succeeded", *testNameArg)
 }
@@ -0,0 +1,118 @@
+package gecko_client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	adminEndpoint = "ext/admin"
+)
+
+type AdminApi struct {
+	rpcRequester jsonRpcRequester
+	ctx          context.Context
+}
+
+// Gets the peers the node is connected to, as seen by the admin API
+func (api AdminApi) GetPeers() ([]Peer, error) {
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, adminEndpoint, "admin.peers", make(map[string]interface{}))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetPeersResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Peers, nil
+}
+
+/*
+Starts profiling the node's CPU usage; the profile is written when StopCPUProfiler is called
+
+Args:
+	fileName: Path inside the node's container that the CPU profile will be written to
+*/
+func (api AdminApi) StartCPUProfiler(fileName string) (bool, error) {
+	params := map[string]interface{}{
+		"fileName": fileName,
+	}
+	return api.makeSuccessRequest("admin.startCPUProfiler", params)
+}
+
+// Stops a CPU profile started with StartCPUProfiler, flushing it to disk
+func (api AdminApi) StopCPUProfiler() (bool, error) {
+	return api.makeSuccessRequest("admin.stopCPUProfiler", make(map[string]interface{}))
+}
+
+/*
+Dumps a snapshot of the node's heap
+
+Args:
+	fileName: Path inside the node's container that the memory profile will be written to
+*/
+func (api AdminApi) MemoryProfile(fileName string) (bool, error) {
+	params := map[string]interface{}{
+		"fileName": fileName,
+	}
+	return api.makeSuccessRequest("admin.memoryProfile", params)
+}
+
+/*
+Dumps the node's mutex statistics
+
+Args:
+	fileName: Path inside the node's container that the lock profile will be written to
+*/
+func (api AdminApi) LockProfile(fileName string) (bool, error) {
+	params := map[string]interface{}{
+		"fileName": fileName,
+	}
+	return api.makeSuccessRequest("admin.lockProfile", params)
+}
+
+/*
+Gives an API endpoint an additional alias
+
+Args:
+	endpoint: The endpoint to alias, without the "/ext/" prefix (e.g. "bc/X")
+	alias: The alias to give the endpoint, after which it will be reachable at "/ext/<alias>"
+*/
+func (api AdminApi) Alias(endpoint string, alias string) (bool, error) {
+	params := map[string]interface{}{
+		"endpoint": endpoint,
+		"alias":    alias,
+	}
+	return api.makeSuccessRequest("admin.alias", params)
+}
+
+/*
+Gives a blockchain an additional alias, usable anywhere the chain's ID is
+
+Args:
+	chain: The ID of the blockchain to alias
+	alias: The alias to give the blockchain
+*/
+func (api AdminApi) AliasChain(chain string, alias string) (bool, error) {
+	params := map[string]interface{}{
+		"chain": chain,
+		"alias": alias,
+	}
+	return api.makeSuccessRequest("admin.aliasChain", params)
+}
+
+// All the state-changing admin methods reply with only a success flag
+func (api AdminApi) makeSuccessRequest(method string, params map[string]interface{}) (bool, error) {
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, adminEndpoint, method, params)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response AdminSuccessResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return false, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Success, nil
+}
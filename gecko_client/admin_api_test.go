@@ -0,0 +1,77 @@
+package gecko_client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const adminSuccessResultStr = `{
+    "jsonrpc": "2.0",
+    "result": {
+        "success": true
+    },
+    "id": 1
+}`
+
+func TestAdminGetPeers(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "peers":[
+          {
+             "ip":"206.189.137.87:9651",
+             "publicIP":"206.189.137.87:9651",
+             "id":"8PYXX47kqLDe2wD4oPbvRRchcnSzMA4J4",
+             "version":"avalanche/0.5.0",
+             "lastSent":"2020-06-01T15:23:02Z",
+             "lastReceived":"2020-06-01T15:22:57Z"
+          }
+        ]
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	peers, err := client.AdminApi().GetPeers()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, 1, len(peers))
+	assert.Equal(t, "8PYXX47kqLDe2wD4oPbvRRchcnSzMA4J4", peers[0].Id)
+}
+
+func TestStartAndStopCPUProfiler(t *testing.T) {
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: adminSuccessResultStr})
+	success, err := client.AdminApi().StartCPUProfiler("cpu.profile")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+
+	success, err = client.AdminApi().StopCPUProfiler()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
+
+func TestMemoryProfile(t *testing.T) {
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: adminSuccessResultStr})
+	success, err := client.AdminApi().MemoryProfile("mem.profile")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
+
+func TestLockProfile(t *testing.T) {
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: adminSuccessResultStr})
+	success, err := client.AdminApi().LockProfile("lock.profile")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
+
+func TestAlias(t *testing.T) {
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: adminSuccessResultStr})
+	success, err := client.AdminApi().Alias("bc/X", "myAlias")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
+
+func TestAliasChain(t *testing.T) {
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: adminSuccessResultStr})
+	success, err := client.AdminApi().AliasChain("sV6o671RtkGBcno1FiaDbVcFv2sG5aVXMZYzKdP4VQAWmJQnM", "myBlockchainAlias")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
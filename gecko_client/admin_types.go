@@ -0,0 +1,11 @@
+package gecko_client
+
+type AdminSuccess struct {
+	Success bool `json:"success"`
+}
+
+type AdminSuccessResponse struct {
+	JsonRpcVersion string       `json:"jsonrpc"`
+	Result         AdminSuccess `json:"result"`
+	Id             int          `json:"id"`
+}
@@ -0,0 +1,187 @@
+package fake_gecko_node
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ================ Keys & Addresses ====================
+// The X-Chain address controlled by the given private key
+func getXchainAddressForKey(privateKey string) string {
+	hash := sha256.Sum256([]byte(privateKey))
+	return xchainAddressPrefix + hex.EncodeToString(hash[:20])
+}
+
+func (node *FakeGeckoNode) avmImportKey(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		credentials
+		PrivateKey string `json:"privateKey"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	address := getXchainAddressForKey(params.PrivateKey)
+	if _, found := node.xchainAddressOwners[address]; !found {
+		// Importing a genesis key gives control of the genesis funds, which can only happen once
+		node.xchainBalances[address] += node.genesisBalances[params.PrivateKey]
+	}
+	node.xchainAddressOwners[address] = params.Username
+	return map[string]interface{}{"address": address}, nil
+}
+
+func (node *FakeGeckoNode) avmCreateAddress(rawParams json.RawMessage) (interface{}, error) {
+	var params credentials
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	address := node.newId(xchainAddressPrefix + "address-")
+	node.xchainAddressOwners[address] = params.Username
+	return map[string]interface{}{"address": address}, nil
+}
+
+func (node *FakeGeckoNode) avmGetBalance(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		Address string `json:"address"`
+		AssetId string `json:"assetID"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	balance := int64(0)
+	if params.AssetId == avaAssetId {
+		balance = node.xchainBalances[params.Address]
+	}
+	return map[string]interface{}{
+		"balance": strconv.FormatInt(balance, 10),
+		"utxoIDs": []interface{}{},
+	}, nil
+}
+
+// ================ Transfers ====================
+// Debits the given amount of AVA from the addresses controlled by the user
+func (node *FakeGeckoNode) spendXchainAva(username string, amount int64) error {
+	available := int64(0)
+	for address, owner := range node.xchainAddressOwners {
+		if owner == username {
+			available += node.xchainBalances[address]
+		}
+	}
+	if available < amount {
+		return fmt.Errorf("insufficient funds: user %v has %v AVA but needs %v", username, available, amount)
+	}
+
+	remaining := amount
+	for address, owner := range node.xchainAddressOwners {
+		if owner != username || remaining == 0 {
+			continue
+		}
+		spent := node.xchainBalances[address]
+		if spent > remaining {
+			spent = remaining
+		}
+		node.xchainBalances[address] -= spent
+		remaining -= spent
+	}
+	return nil
+}
+
+func (node *FakeGeckoNode) avmSend(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		credentials
+		Amount  int64  `json:"amount"`
+		AssetId string `json:"assetID"`
+		To      string `json:"to"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	if params.AssetId != avaAssetId {
+		return nil, fmt.Errorf("unknown asset %v", params.AssetId)
+	}
+	if err := node.spendXchainAva(params.Username, params.Amount); err != nil {
+		return nil, err
+	}
+	node.xchainBalances[params.To] += params.Amount
+	return map[string]interface{}{"txID": node.newXchainTx()}, nil
+}
+
+func (node *FakeGeckoNode) avmGetTxStatus(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		TxId string `json:"txID"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	status, found := node.xchainTxStatuses[params.TxId]
+	if !found {
+		status = unknownStatus
+	}
+	return map[string]interface{}{"status": status}, nil
+}
+
+// Exports AVA from the X-Chain to a P-Chain account, where it must then be imported
+func (node *FakeGeckoNode) avmExportAva(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		credentials
+		To     string `json:"to"`
+		Amount int64  `json:"amount"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	if err := node.spendXchainAva(params.Username, params.Amount); err != nil {
+		return nil, err
+	}
+	node.pendingPchainImports[params.To] += params.Amount
+	return map[string]interface{}{"txID": node.newXchainTx()}, nil
+}
+
+// Imports AVA which was exported from the P-Chain to the given X-Chain address
+func (node *FakeGeckoNode) avmImportAva(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		credentials
+		To string `json:"to"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	amount := node.pendingXchainImports[params.To]
+	if amount == 0 {
+		return nil, fmt.Errorf("problem issuing transaction: no import inputs")
+	}
+	delete(node.pendingXchainImports, params.To)
+	node.xchainBalances[params.To] += amount
+	return map[string]interface{}{"txID": node.newXchainTx()}, nil
+}
+
+// Accepts any transaction, without interpreting it
+func (node *FakeGeckoNode) avmIssueTx(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		Tx string `json:"tx"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if params.Tx == "" {
+		return nil, fmt.Errorf("missing tx")
+	}
+	return map[string]interface{}{"txID": node.newXchainTx()}, nil
+}
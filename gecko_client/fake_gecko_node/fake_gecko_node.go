@@ -0,0 +1,315 @@
+package fake_gecko_node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	jsonRpcVersion = "2.0"
+
+	// Gorilla RPC, which Gecko uses, reports all errors returned by service methods with this code
+	serverErrorCode    = -32000
+	methodNotFoundCode = -32601
+
+	xchainAddressPrefix = "X-"
+	avaAssetId          = "AVA"
+
+	acceptedStatus = "Accepted"
+	unknownStatus  = "Unknown"
+)
+
+// The endpoint that each JSON RPC method namespace is served on
+var namespaceEndpoints = map[string]string{
+	"health":   "/ext/health",
+	"info":     "/ext/info",
+	"keystore": "/ext/keystore",
+	"avm":      "/ext/bc/X",
+	"platform": "/ext/P",
+}
+
+type jsonRpcRequest struct {
+	JsonRpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Id      int             `json:"id"`
+}
+
+type jsonRpcError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+}
+
+type jsonRpcResponse struct {
+	JsonRpc string        `json:"jsonrpc"`
+	Result  interface{}   `json:"result,omitempty"`
+	Error   *jsonRpcError `json:"error,omitempty"`
+	Id      int           `json:"id"`
+}
+
+// Handles a single JSON RPC method, returning the result object or an error to report to the caller
+type methodHandler func(params json.RawMessage) (interface{}, error)
+
+/*
+An in-memory, single-node imitation of Gecko's JSON RPC API, backed by an httptest server, which lets code built on top
+of the Gecko client be unit-tested without Docker or a Gecko image.
+
+Transactions are accepted the instant they're issued, and cross-chain transfers are importable the instant they're
+exported; the only thing which takes real time is a validator's start time arriving.
+*/
+type FakeGeckoNode struct {
+	server *httptest.Server
+	nodeId string
+
+	// Guards all the state below, which is mutated by concurrent HTTP handlers
+	mutex *sync.Mutex
+
+	// Used to generate unique IDs for addresses and transactions
+	idCounter int
+
+	// Username -> password
+	users map[string]string
+
+	// Private key -> X-Chain AVA balance of the address that importing the key will give control of
+	genesisBalances map[string]int64
+
+	xchainAddressOwners  map[string]string
+	xchainBalances       map[string]int64
+	xchainTxStatuses     map[string]string
+	pchainAccounts       map[string]*pchainAccount
+	pchainTxs            map[string]*pchainTx
+	pendingPchainImports map[string]int64
+	pendingXchainImports map[string]int64
+	defaultSubnetStakers []staker
+
+	// Name -> message of every health check the node reports as failing
+	failingHealthChecks map[string]string
+}
+
+/*
+Creates a fake Gecko node and starts serving its API
+
+Args:
+	nodeId: The node ID the fake will report for itself
+	genesisBalances: Mapping of private key -> AVA balance, for keys which control funded X-Chain addresses at genesis
+*/
+func NewFakeGeckoNode(nodeId string, genesisBalances map[string]int64) *FakeGeckoNode {
+	genesisBalancesCopy := make(map[string]int64)
+	for privateKey, balance := range genesisBalances {
+		genesisBalancesCopy[privateKey] = balance
+	}
+	node := &FakeGeckoNode{
+		nodeId:               nodeId,
+		mutex:                &sync.Mutex{},
+		users:                make(map[string]string),
+		genesisBalances:      genesisBalancesCopy,
+		xchainAddressOwners:  make(map[string]string),
+		xchainBalances:       make(map[string]int64),
+		xchainTxStatuses:     make(map[string]string),
+		pchainAccounts:       make(map[string]*pchainAccount),
+		pchainTxs:            make(map[string]*pchainTx),
+		pendingPchainImports: make(map[string]int64),
+		pendingXchainImports: make(map[string]int64),
+		defaultSubnetStakers: []staker{},
+		failingHealthChecks:  make(map[string]string),
+	}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	return node
+}
+
+func (node *FakeGeckoNode) GetIpAddr() string {
+	host, _, _ := net.SplitHostPort(node.server.Listener.Addr().String())
+	return host
+}
+
+func (node *FakeGeckoNode) GetPort() nat.Port {
+	_, port, _ := net.SplitHostPort(node.server.Listener.Addr().String())
+	return nat.Port(port + "/tcp")
+}
+
+func (node *FakeGeckoNode) Close() {
+	node.server.Close()
+}
+
+/*
+Makes the node report the given health check as failing, and itself as unhealthy, from now on
+
+Args:
+	checkName: The name of the check, e.g. "network.validators.heartbeat"
+	message: The message the check will report
+*/
+func (node *FakeGeckoNode) FailHealthCheck(checkName string, message string) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+	node.failingHealthChecks[checkName] = message
+}
+
+func (node *FakeGeckoNode) getHandlers() map[string]methodHandler {
+	return map[string]methodHandler{
+		"health.getLiveness": node.getLiveness,
+
+		"info.getNodeID":      node.getNodeId,
+		"info.peers":          node.getPeers,
+		"info.isBootstrapped": node.isBootstrapped,
+
+		"keystore.createUser": node.createUser,
+
+		"avm.importKey":     node.avmImportKey,
+		"avm.createAddress": node.avmCreateAddress,
+		"avm.getBalance":    node.avmGetBalance,
+		"avm.send":          node.avmSend,
+		"avm.getTxStatus":   node.avmGetTxStatus,
+		"avm.exportAVA":     node.avmExportAva,
+		"avm.importAVA":     node.avmImportAva,
+		"avm.issueTx":       node.avmIssueTx,
+
+		"platform.createAccount":             node.platformCreateAccount,
+		"platform.getAccount":                node.platformGetAccount,
+		"platform.listAccounts":              node.platformListAccounts,
+		"platform.importAVA":                 node.platformImportAva,
+		"platform.exportAVA":                 node.platformExportAva,
+		"platform.addDefaultSubnetValidator": node.platformAddDefaultSubnetValidator,
+		"platform.addDefaultSubnetDelegator": node.platformAddDefaultSubnetDelegator,
+		"platform.sign":                      node.platformSign,
+		"platform.issueTx":                   node.platformIssueTx,
+		"platform.getCurrentValidators":      node.platformGetCurrentValidators,
+		"platform.getPendingValidators":      node.platformGetPendingValidators,
+	}
+}
+
+func (node *FakeGeckoNode) serveHTTP(writer http.ResponseWriter, httpRequest *http.Request) {
+	if httpRequest.Method != http.MethodPost {
+		http.Error(writer, "JSON RPC requests must be POSTs", http.StatusMethodNotAllowed)
+		return
+	}
+	requestBodyBytes, err := ioutil.ReadAll(httpRequest.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var request jsonRpcRequest
+	if err := json.Unmarshal(requestBodyBytes, &request); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	namespace := strings.Split(request.Method, ".")[0]
+	if endpoint, found := namespaceEndpoints[namespace]; !found || endpoint != httpRequest.URL.Path {
+		http.NotFound(writer, httpRequest)
+		return
+	}
+
+	response := jsonRpcResponse{
+		JsonRpc: jsonRpcVersion,
+		Id:      request.Id,
+	}
+	handler, found := node.getHandlers()[request.Method]
+	if found {
+		node.mutex.Lock()
+		result, err := handler(request.Params)
+		node.mutex.Unlock()
+		if err != nil {
+			response.Error = &jsonRpcError{Code: serverErrorCode, Message: err.Error()}
+		} else {
+			response.Result = result
+		}
+	} else {
+		response.Error = &jsonRpcError{
+			Code:    methodNotFoundCode,
+			Message: fmt.Sprintf("method %v not found", request.Method),
+		}
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(writer).Encode(response); err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ================ Helpers ====================
+func (node *FakeGeckoNode) newId(prefix string) string {
+	node.idCounter++
+	return fmt.Sprintf("%v%v", prefix, node.idCounter)
+}
+
+func (node *FakeGeckoNode) newXchainTx() string {
+	txId := node.newId("tx-")
+	node.xchainTxStatuses[txId] = acceptedStatus
+	return txId
+}
+
+func parseParams(rawParams json.RawMessage, params interface{}) error {
+	if err := json.Unmarshal(rawParams, params); err != nil {
+		return fmt.Errorf("couldn't parse params: %v", err)
+	}
+	return nil
+}
+
+func (node *FakeGeckoNode) authenticate(username string, password string) error {
+	expectedPassword, found := node.users[username]
+	if !found {
+		return fmt.Errorf("user doesn't exist: %v", username)
+	}
+	if expectedPassword != password {
+		return fmt.Errorf("incorrect password for user %v", username)
+	}
+	return nil
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ================ Health & Info ====================
+func (node *FakeGeckoNode) getLiveness(rawParams json.RawMessage) (interface{}, error) {
+	checks := map[string]interface{}{}
+	for checkName, message := range node.failingHealthChecks {
+		checks[checkName] = map[string]interface{}{
+			"message":            message,
+			"timestamp":          time.Now(),
+			"duration":           0,
+			"contiguousFailures": 1,
+			"timeOfFirstFailure": time.Now(),
+		}
+	}
+	return map[string]interface{}{
+		"checks":  checks,
+		"healthy": len(node.failingHealthChecks) == 0,
+	}, nil
+}
+
+func (node *FakeGeckoNode) getNodeId(rawParams json.RawMessage) (interface{}, error) {
+	return map[string]interface{}{"nodeID": node.nodeId}, nil
+}
+
+func (node *FakeGeckoNode) getPeers(rawParams json.RawMessage) (interface{}, error) {
+	return map[string]interface{}{"peers": []interface{}{}}, nil
+}
+
+func (node *FakeGeckoNode) isBootstrapped(rawParams json.RawMessage) (interface{}, error) {
+	return map[string]interface{}{"isBootstrapped": true}, nil
+}
+
+// ================ Keystore ====================
+func (node *FakeGeckoNode) createUser(rawParams json.RawMessage) (interface{}, error) {
+	var params credentials
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if _, found := node.users[params.Username]; found {
+		return nil, fmt.Errorf("user already exists: %v", params.Username)
+	}
+	node.users[params.Username] = params.Password
+	return map[string]interface{}{"success": true}, nil
+}
@@ -0,0 +1,327 @@
+package fake_gecko_node
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+type pchainAccount struct {
+	owner   string
+	nonce   int
+	balance int64
+}
+
+type pchainTxKind int
+
+const (
+	importAvaTx pchainTxKind = iota
+	exportAvaTx
+	addValidatorTx
+	addDelegatorTx
+)
+
+// A P-Chain transaction that has been created but not yet issued
+type pchainTx struct {
+	kind       pchainTxKind
+	payerNonce int
+
+	// Empty if the transaction hasn't been signed yet
+	payer string
+
+	// Recipient address for AVA transfers, or node ID for validators & delegators
+	to     string
+	amount int64
+
+	startTime int64
+	endTime   int64
+}
+
+// A validator or delegator of the default subnet
+type staker struct {
+	isValidator bool
+	nodeId      string
+	startTime   int64
+	endTime     int64
+	stakeAmount int64
+}
+
+// ================ Accounts ====================
+func (node *FakeGeckoNode) platformCreateAccount(rawParams json.RawMessage) (interface{}, error) {
+	var params credentials
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	address := node.newId("pchain-address-")
+	node.pchainAccounts[address] = &pchainAccount{owner: params.Username}
+	return map[string]interface{}{"address": address}, nil
+}
+
+func serializeAccount(address string, account *pchainAccount) map[string]interface{} {
+	return map[string]interface{}{
+		"address": address,
+		"nonce":   strconv.Itoa(account.nonce),
+		"balance": strconv.FormatInt(account.balance, 10),
+	}
+}
+
+func (node *FakeGeckoNode) platformGetAccount(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		Address string `json:"address"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	account, found := node.pchainAccounts[params.Address]
+	if !found {
+		// Like Gecko, addresses that have never been used are reported as empty accounts
+		account = &pchainAccount{}
+	}
+	return serializeAccount(params.Address, account), nil
+}
+
+func (node *FakeGeckoNode) platformListAccounts(rawParams json.RawMessage) (interface{}, error) {
+	var params credentials
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	accounts := []interface{}{}
+	for address, account := range node.pchainAccounts {
+		if account.owner == params.Username {
+			accounts = append(accounts, serializeAccount(address, account))
+		}
+	}
+	return map[string]interface{}{"accounts": accounts}, nil
+}
+
+// ================ Transactions ====================
+func (node *FakeGeckoNode) addUnsignedTx(tx pchainTx) interface{} {
+	unsignedTx := node.newId("unsigned-tx-")
+	node.pchainTxs[unsignedTx] = &tx
+	return map[string]interface{}{"unsignedTx": unsignedTx}
+}
+
+// Unlike the other transaction-creating methods, importAVA returns a transaction that's already signed by the recipient
+func (node *FakeGeckoNode) platformImportAva(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		credentials
+		To         string `json:"to"`
+		PayerNonce int    `json:"payerNonce"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	if node.pendingPchainImports[params.To] == 0 {
+		return nil, fmt.Errorf("problem issuing transaction: no import inputs")
+	}
+	signedTx := node.newId("signed-tx-")
+	node.pchainTxs[signedTx] = &pchainTx{
+		kind:       importAvaTx,
+		payerNonce: params.PayerNonce,
+		payer:      params.To,
+		to:         params.To,
+	}
+	return map[string]interface{}{"tx": signedTx}, nil
+}
+
+func (node *FakeGeckoNode) platformExportAva(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		Amount     int64  `json:"amount"`
+		To         string `json:"to"`
+		PayerNonce int    `json:"payerNonce"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	return node.addUnsignedTx(pchainTx{
+		kind:       exportAvaTx,
+		payerNonce: params.PayerNonce,
+		// The P-Chain API takes X-Chain addresses without their prefix
+		to:     xchainAddressPrefix + params.To,
+		amount: params.Amount,
+	}), nil
+}
+
+type addStakerParams struct {
+	Id          string `json:"id"`
+	PayerNonce  int    `json:"payerNonce"`
+	Destination string `json:"destination"`
+	StartTime   int64  `json:"startTime"`
+	EndTime     int64  `json:"endTime"`
+	StakeAmount int64  `json:"stakeAmount"`
+}
+
+func (node *FakeGeckoNode) platformAddDefaultSubnetValidator(rawParams json.RawMessage) (interface{}, error) {
+	var params addStakerParams
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	return node.addStakerTx(addValidatorTx, params)
+}
+
+func (node *FakeGeckoNode) platformAddDefaultSubnetDelegator(rawParams json.RawMessage) (interface{}, error) {
+	var params addStakerParams
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	return node.addStakerTx(addDelegatorTx, params)
+}
+
+func (node *FakeGeckoNode) addStakerTx(kind pchainTxKind, params addStakerParams) (interface{}, error) {
+	if params.StartTime >= params.EndTime {
+		return nil, fmt.Errorf("start time %v must be before end time %v", params.StartTime, params.EndTime)
+	}
+	return node.addUnsignedTx(pchainTx{
+		kind:       kind,
+		payerNonce: params.PayerNonce,
+		to:         params.Id,
+		amount:     params.StakeAmount,
+		startTime:  params.StartTime,
+		endTime:    params.EndTime,
+	}), nil
+}
+
+func (node *FakeGeckoNode) platformSign(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		credentials
+		Tx     string `json:"tx"`
+		Signer string `json:"signer"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if err := node.authenticate(params.Username, params.Password); err != nil {
+		return nil, err
+	}
+	tx, found := node.pchainTxs[params.Tx]
+	if !found {
+		return nil, fmt.Errorf("couldn't parse tx %v", params.Tx)
+	}
+	signerAccount, found := node.pchainAccounts[params.Signer]
+	if !found || signerAccount.owner != params.Username {
+		return nil, fmt.Errorf("user %v doesn't control address %v", params.Username, params.Signer)
+	}
+	signedTx := *tx
+	signedTx.payer = params.Signer
+	signedTxStr := node.newId("signed-tx-")
+	node.pchainTxs[signedTxStr] = &signedTx
+	return map[string]interface{}{"Tx": signedTxStr}, nil
+}
+
+func (node *FakeGeckoNode) platformIssueTx(rawParams json.RawMessage) (interface{}, error) {
+	var params struct {
+		Tx string `json:"tx"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	tx, found := node.pchainTxs[params.Tx]
+	if !found {
+		return nil, fmt.Errorf("couldn't parse tx %v", params.Tx)
+	}
+	if tx.payer == "" {
+		return nil, fmt.Errorf("tx %v hasn't been signed", params.Tx)
+	}
+	payerAccount, found := node.pchainAccounts[tx.payer]
+	if !found {
+		payerAccount = &pchainAccount{}
+		node.pchainAccounts[tx.payer] = payerAccount
+	}
+	if tx.payerNonce != payerAccount.nonce+1 {
+		return nil, fmt.Errorf("invalid nonce %v for account %v, expected %v", tx.payerNonce, tx.payer, payerAccount.nonce+1)
+	}
+
+	switch tx.kind {
+	case importAvaTx:
+		amount := node.pendingPchainImports[tx.to]
+		if amount == 0 {
+			return nil, fmt.Errorf("problem issuing transaction: no import inputs")
+		}
+		delete(node.pendingPchainImports, tx.to)
+		payerAccount.balance += amount
+	case exportAvaTx:
+		if payerAccount.balance < tx.amount {
+			return nil, fmt.Errorf("insufficient funds: account %v has %v AVA but needs %v", tx.payer, payerAccount.balance, tx.amount)
+		}
+		payerAccount.balance -= tx.amount
+		node.pendingXchainImports[tx.to] += tx.amount
+	case addValidatorTx, addDelegatorTx:
+		if payerAccount.balance < tx.amount {
+			return nil, fmt.Errorf("insufficient funds: account %v has %v AVA but needs %v to stake", tx.payer, payerAccount.balance, tx.amount)
+		}
+		if tx.kind == addDelegatorTx && !node.isValidator(tx.to) {
+			return nil, fmt.Errorf("delegatee %v is not a validator of the default subnet", tx.to)
+		}
+		payerAccount.balance -= tx.amount
+		node.defaultSubnetStakers = append(node.defaultSubnetStakers, staker{
+			isValidator: tx.kind == addValidatorTx,
+			nodeId:      tx.to,
+			startTime:   tx.startTime,
+			endTime:     tx.endTime,
+			stakeAmount: tx.amount,
+		})
+	}
+	payerAccount.nonce++
+	// Transactions can only be issued once
+	delete(node.pchainTxs, params.Tx)
+	return map[string]interface{}{"txID": node.newId("tx-")}, nil
+}
+
+// ================ Validators ====================
+func (node *FakeGeckoNode) isValidator(nodeId string) bool {
+	for _, staker := range node.defaultSubnetStakers {
+		if staker.isValidator && staker.nodeId == nodeId {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the default subnet validators for which the given filter returns true
+func (node *FakeGeckoNode) getValidators(rawParams json.RawMessage, filter func(staker staker, now int64) bool) (interface{}, error) {
+	var params struct {
+		SubnetId *string `json:"subnetID"`
+	}
+	if err := parseParams(rawParams, &params); err != nil {
+		return nil, err
+	}
+	if params.SubnetId != nil {
+		return nil, fmt.Errorf("fake Gecko node only supports the default subnet, not %v", *params.SubnetId)
+	}
+	now := time.Now().Unix()
+	validators := []interface{}{}
+	for _, staker := range node.defaultSubnetStakers {
+		if !staker.isValidator || !filter(staker, now) {
+			continue
+		}
+		validators = append(validators, map[string]interface{}{
+			"id":          staker.nodeId,
+			"startTime":   strconv.FormatInt(staker.startTime, 10),
+			"endTime":     strconv.FormatInt(staker.endTime, 10),
+			"stakeAmount": strconv.FormatInt(staker.stakeAmount, 10),
+		})
+	}
+	return map[string]interface{}{"validators": validators}, nil
+}
+
+func (node *FakeGeckoNode) platformGetCurrentValidators(rawParams json.RawMessage) (interface{}, error) {
+	return node.getValidators(rawParams, func(staker staker, now int64) bool {
+		return staker.startTime <= now && now < staker.endTime
+	})
+}
+
+func (node *FakeGeckoNode) platformGetPendingValidators(rawParams json.RawMessage) (interface{}, error) {
+	return node.getValidators(rawParams, func(staker staker, now int64) bool {
+		return now < staker.startTime
+	})
+}
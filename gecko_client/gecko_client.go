@@ -1,6 +1,7 @@
 package gecko_client
 
 import (
+	"context"
 	"github.com/docker/go-connections/nat"
 	"time"
 )
@@ -10,11 +11,10 @@ const (
 )
 
 type GeckoClient struct {
-	pChainApi   PChainApi
-	xChainApi   XChainApi
-	infoApi     InfoApi
-	healthApi   HealthApi
-	keystoreApi KeystoreApi
+	rpcRequester jsonRpcRequester
+
+	// Every request made through this client's APIs will be cancelled when this context is done
+	ctx context.Context
 }
 
 func NewGeckoClient(ipAddr string, port nat.Port) *GeckoClient {
@@ -25,30 +25,86 @@ func NewGeckoClient(ipAddr string, port nat.Port) *GeckoClient {
 // This method is exposed for mocking the Gecko client
 func clientFromRequester(requester jsonRpcRequester) *GeckoClient {
 	return &GeckoClient{
-		pChainApi:   PChainApi{rpcRequester: requester},
-		xChainApi:   XChainApi{rpcRequester: requester},
-		infoApi:     InfoApi{rpcRequester: requester},
-		healthApi:   HealthApi{rpcRequester: requester},
-		keystoreApi: KeystoreApi{rpcRequester: requester},
+		rpcRequester: requester,
+		ctx:          context.Background(),
+	}
+}
+
+/*
+Returns a copy of this client whose requests are all bound to the given context, so that cancelling the context (or
+hitting its deadline) aborts any in-flight requests
+*/
+func (client GeckoClient) WithContext(ctx context.Context) *GeckoClient {
+	return &GeckoClient{
+		rpcRequester: client.rpcRequester,
+		ctx:          ctx,
+	}
+}
+
+// Gets the context the client's requests are bound to, which anything waiting on the client should also be bound to
+func (client GeckoClient) GetContext() context.Context {
+	return client.ctx
+}
+
+/*
+Returns a copy of this client which retries failed requests according to the given policy, or an unchanged copy if the
+policy is nil
+*/
+func (client GeckoClient) WithRetryPolicy(policy *RetryPolicy) *GeckoClient {
+	if policy == nil {
+		return &client
+	}
+	return &GeckoClient{
+		rpcRequester: retryingJsonRpcRequester{
+			wrapped: client.rpcRequester,
+			policy:  *policy,
+		},
+		ctx: client.ctx,
+	}
+}
+
+/*
+Returns a copy of this client which records every request it makes with the given recorder
+
+Args:
+	recorder: The recorder to write recordings to
+	serviceId: ID of the service that this client talks to, to identify which node each recording came from
+*/
+func (client GeckoClient) WithRecorder(recorder *RpcRecorder, serviceId string) *GeckoClient {
+	return &GeckoClient{
+		rpcRequester: recordingJsonRpcRequester{
+			wrapped:   client.rpcRequester,
+			recorder:  recorder,
+			serviceId: serviceId,
+		},
+		ctx: client.ctx,
 	}
 }
 
 func (client GeckoClient) PChainApi() PChainApi {
-	return client.pChainApi
+	return PChainApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
 }
 
 func (client GeckoClient) XChainApi() XChainApi {
-	return client.xChainApi
+	return XChainApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
 }
 
 func (client GeckoClient) InfoApi() InfoApi {
-	return client.infoApi
+	return InfoApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
 }
 
 func (client GeckoClient) HealthApi() HealthApi {
-	return client.healthApi
+	return HealthApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
 }
 
 func (client GeckoClient) KeystoreApi() KeystoreApi {
-	return client.keystoreApi
+	return KeystoreApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
+}
+
+func (client GeckoClient) AdminApi() AdminApi {
+	return AdminApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
+}
+
+func (client GeckoClient) IpcsApi() IpcsApi {
+	return IpcsApi{rpcRequester: client.rpcRequester, ctx: client.ctx}
 }
@@ -1,11 +1,10 @@
 package gecko_client
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/palantir/stacktrace"
-
-	"github.com/ava-labs/gecko/api/health"
 )
 
 const (
@@ -14,17 +13,18 @@ const (
 
 type HealthApi struct {
 	rpcRequester jsonRpcRequester
+	ctx          context.Context
 }
 
-func (api HealthApi) GetLiveness() (health.GetLivenessReply, error) {
+func (api HealthApi) GetLiveness() (*LivenessInfo, error) {
 	var response GetLivenessResponse
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(healthApiEndpoint, "health.getLiveness", make(map[string]interface{}))
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, healthApiEndpoint, "health.getLiveness", make(map[string]interface{}))
 	if err != nil {
-		return health.GetLivenessReply{}, stacktrace.Propagate(err, "Error getting liveness")
+		return nil, stacktrace.Propagate(err, "Error getting liveness")
 	}
 
 	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
-		return health.GetLivenessReply{}, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
 	}
-	return response.Result, nil
+	return &response.Result, nil
 }
@@ -2,6 +2,9 @@ package gecko_client
 
 import (
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 type healthTest struct {
@@ -36,7 +39,37 @@ func TestGetLiveness(t *testing.T) {
 			expectedHealthy: true,
 			nilError:        true,
 		},
-		// TODO fix health API to not error when parsing health check that contains an error
+		{
+			resultString: `{
+            "jsonrpc":"2.0",
+            "result":{
+               "checks":{
+                  "network.validators.heartbeat":{
+                     "message":{
+                        "heartbeat":1591041377
+                     },
+                     "error":{},
+                     "timestamp":"2020-06-01T15:56:18.554202-04:00",
+                     "duration":23201,
+                     "contiguousFailures":3,
+                     "timeOfFirstFailure":"2020-06-01T15:55:48.554202-04:00"
+                  },
+                  "chains.default.bootstrapped":{
+                     "message":["X", "P"],
+                     "timestamp":"2020-06-01T15:56:18.554202-04:00",
+                     "duration":1200,
+                     "contiguousFailures":0,
+                     "timeOfFirstFailure":null
+                  }
+               },
+               "healthy":false
+            },
+            "id":1
+         }`,
+			expectedChecks:  2,
+			expectedHealthy: false,
+			nilError:        true,
+		},
 	}
 
 	for _, test := range tests {
@@ -44,15 +77,87 @@ func TestGetLiveness(t *testing.T) {
 		livenessInfo, err := client.HealthApi().GetLiveness()
 
 		if test.nilError && err != nil {
-			t.Fatalf("Expected error to be nil, but found: %w", err)
+			t.Fatalf("Expected error to be nil, but found: %v", err)
 		}
 
 		if test.expectedChecks != len(livenessInfo.Checks) {
 			t.Fatalf("Expected to find: %d checks, but instead found %d checks", test.expectedChecks, len(livenessInfo.Checks))
 		}
 
-		if test.expectedHealthy && !livenessInfo.Healthy {
-			t.Fatal("Unexpectedly returned unhealthy")
+		if test.expectedHealthy != livenessInfo.Healthy {
+			t.Fatalf("Expected healthy to be %v, but was %v", test.expectedHealthy, livenessInfo.Healthy)
 		}
 	}
 }
+
+func TestFailingCheckDetails(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "result":{
+        "checks":{
+            "network.validators.heartbeat":{
+                "message":{"heartbeat":1591041377},
+                "timestamp":"2020-06-01T15:56:18.554202-04:00",
+                "duration":23201,
+                "contiguousFailures":3,
+                "timeOfFirstFailure":"2020-06-01T15:55:48.554202-04:00"
+            },
+            "chains.default.bootstrapped":{
+                "message":"all chains bootstrapped",
+                "timestamp":"2020-06-01T15:56:18.554202-04:00",
+                "duration":1200,
+                "contiguousFailures":0,
+                "timeOfFirstFailure":null
+            }
+        },
+        "healthy":false
+    },
+    "id":1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	livenessInfo, err := client.HealthApi().GetLiveness()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, []string{"network.validators.heartbeat"}, livenessInfo.GetFailingChecks())
+
+	heartbeat := livenessInfo.Checks["network.validators.heartbeat"]
+	assert.Equal(t, int64(3), heartbeat.ContiguousFailures)
+	assert.NotNil(t, heartbeat.TimeOfFirstFailure)
+	assert.Equal(t, 30*time.Second, heartbeat.Timestamp.Sub(*heartbeat.TimeOfFirstFailure))
+	assert.Equal(t, 23201*time.Nanosecond, heartbeat.Duration)
+
+	bootstrapped := livenessInfo.Checks["chains.default.bootstrapped"]
+	assert.True(t, bootstrapped.IsPassing())
+	assert.Nil(t, bootstrapped.TimeOfFirstFailure)
+	assert.Equal(t, "all chains bootstrapped", bootstrapped.Message)
+
+	assert.False(t, AllChecksPassing()(livenessInfo))
+	assert.True(t, CheckPassing("chains.default.bootstrapped")(livenessInfo))
+	assert.False(t, CheckPassing("network.validators.heartbeat")(livenessInfo))
+	assert.False(t, CheckPassing("nonexistent.check")(livenessInfo))
+}
+
+func TestAllChecksPassingFor(t *testing.T) {
+	healthy := &LivenessInfo{
+		Checks:  map[string]Check{"network.validators.heartbeat": {ContiguousFailures: 0}},
+		Healthy: true,
+	}
+	unhealthy := &LivenessInfo{
+		Checks:  map[string]Check{"network.validators.heartbeat": {ContiguousFailures: 1}},
+		Healthy: false,
+	}
+
+	currentTime := time.Unix(0, 0)
+	predicate := allChecksPassingFor(10*time.Second, func() time.Time { return currentTime })
+
+	assert.False(t, predicate(healthy), "Checks have only just started passing")
+	currentTime = currentTime.Add(5 * time.Second)
+	assert.False(t, predicate(unhealthy))
+
+	// The failure should restart the clock
+	currentTime = currentTime.Add(time.Second)
+	assert.False(t, predicate(healthy))
+	currentTime = currentTime.Add(9 * time.Second)
+	assert.False(t, predicate(healthy))
+	currentTime = currentTime.Add(time.Second)
+	assert.True(t, predicate(healthy))
+}
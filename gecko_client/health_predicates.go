@@ -0,0 +1,54 @@
+package gecko_client
+
+import (
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
+)
+
+// Predicates over the *LivenessInfo returned by HealthApi.GetLiveness, for polling a node until it's healthy
+
+// Returns a predicate that's true when the node reports itself healthy and none of its checks are failing
+func AllChecksPassing() poller.Predicate {
+	return func(value interface{}) bool {
+		info, ok := value.(*LivenessInfo)
+		return ok && info.Healthy && len(info.GetFailingChecks()) == 0
+	}
+}
+
+// Returns a predicate that's true when the check with the given name exists and is passing
+func CheckPassing(checkName string) poller.Predicate {
+	return func(value interface{}) bool {
+		info, ok := value.(*LivenessInfo)
+		if !ok {
+			return false
+		}
+		check, found := info.Checks[checkName]
+		return found && check.IsPassing()
+	}
+}
+
+/*
+Returns a predicate that's true once every observation for at least the given duration has had all checks passing; any
+observation with a failing check restarts the clock. The returned predicate is stateful, so a new one must be created
+for each poll.
+*/
+func AllChecksPassingFor(duration time.Duration) poller.Predicate {
+	return allChecksPassingFor(duration, time.Now)
+}
+
+func allChecksPassingFor(duration time.Duration, now func() time.Time) poller.Predicate {
+	allChecksPassing := AllChecksPassing()
+	var passingSince *time.Time
+	return func(value interface{}) bool {
+		if !allChecksPassing(value) {
+			passingSince = nil
+			return false
+		}
+		observationTime := now()
+		if passingSince == nil {
+			passingSince = &observationTime
+		}
+		return observationTime.Sub(*passingSince) >= duration
+	}
+}
@@ -1,13 +1,29 @@
 package gecko_client
 
-import "github.com/ava-labs/gecko/api/health"
+import (
+	"sort"
+	"time"
+)
 
+// The result of the most recent run of one of a node's named health checks
 type Check struct {
-	Message            map[string]interface{} `json:"message"`
-	Timestamp          string                 `json:"timestamp"`
-	Duration           int                    `json:"duration"`
-	ContiguousFailures int                    `json:"contiguousFailures"`
-	TimeOfFirstFailure string                 `json:"timeOfFirstFailure"`
+	// Free-form details about the check's result, whose shape depends on the check
+	Message interface{} `json:"message"`
+
+	// Details about why the check failed, if it did; Gecko doesn't give errors a consistent shape
+	Error interface{} `json:"error,omitempty"`
+
+	// When the check was last run, and how long it took
+	Timestamp time.Time     `json:"timestamp"`
+	Duration  time.Duration `json:"duration"`
+
+	// How many times in a row the check has failed, and when the first of those failures was (nil if the check is passing)
+	ContiguousFailures int64      `json:"contiguousFailures"`
+	TimeOfFirstFailure *time.Time `json:"timeOfFirstFailure"`
+}
+
+func (check Check) IsPassing() bool {
+	return check.ContiguousFailures == 0
 }
 
 type LivenessInfo struct {
@@ -15,8 +31,20 @@ type LivenessInfo struct {
 	Healthy bool             `json:"healthy"`
 }
 
+// Gets the names of the checks which are currently failing, sorted
+func (info LivenessInfo) GetFailingChecks() []string {
+	result := make([]string, 0)
+	for name, check := range info.Checks {
+		if !check.IsPassing() {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 type GetLivenessResponse struct {
-	JsonRpcVersion string                  `json:"jsonrpc"`
-	Result         health.GetLivenessReply `json:"result"`
-	Id             int                     `json:"id"`
+	JsonRpcVersion string       `json:"jsonrpc"`
+	Result         LivenessInfo `json:"result"`
+	Id             int          `json:"id"`
 }
@@ -1,20 +1,23 @@
 package gecko_client
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/palantir/stacktrace"
+	"strconv"
 )
 
 const (
-	adminEndpoint = "ext/info"
+	infoEndpoint = "ext/info"
 )
 
 type InfoApi struct {
 	rpcRequester jsonRpcRequester
+	ctx          context.Context
 }
 
 func (api InfoApi) GetPeers() ([]Peer, error) {
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(adminEndpoint, "info.peers", make(map[string]interface{}))
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.peers", make(map[string]interface{}))
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "Error making request")
 	}
@@ -27,7 +30,7 @@ func (api InfoApi) GetPeers() ([]Peer, error) {
 }
 
 func (api InfoApi) GetNodeId() (string, error) {
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(adminEndpoint, "info.getNodeID", make(map[string]interface{}))
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.getNodeID", make(map[string]interface{}))
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -38,3 +41,84 @@ func (api InfoApi) GetNodeId() (string, error) {
 	}
 	return response.Result.NodeID, nil
 }
+
+// Returns whether the chain with the given ID or alias (e.g. "X" or "P") has finished bootstrapping on the node
+func (api InfoApi) IsBootstrapped(chain string) (bool, error) {
+	params := map[string]interface{}{
+		"chain": chain,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.isBootstrapped", params)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response IsBootstrappedResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return false, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.IsBootstrapped, nil
+}
+
+// Returns the ID of the network the node is participating in
+func (api InfoApi) GetNetworkId() (uint32, error) {
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.getNetworkID", make(map[string]interface{}))
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetNetworkIDResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return 0, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	// Gecko serializes the network ID as a string
+	networkId, err := strconv.ParseUint(response.Result.NetworkID, 10, 32)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Could not parse network ID '%v'", response.Result.NetworkID)
+	}
+	return uint32(networkId), nil
+}
+
+// Returns the name of the network the node is participating in (e.g. "local")
+func (api InfoApi) GetNetworkName() (string, error) {
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.getNetworkName", make(map[string]interface{}))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetNetworkNameResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.NetworkName, nil
+}
+
+// Returns the version the node is running (e.g. "avalanche/0.5.7"), in the same form that its peers report it in
+func (api InfoApi) GetNodeVersion() (string, error) {
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.getNodeVersion", make(map[string]interface{}))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetNodeVersionResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Version, nil
+}
+
+// Returns the ID of the blockchain with the given alias (e.g. "X" or "P")
+func (api InfoApi) GetBlockchainId(alias string) (string, error) {
+	params := map[string]interface{}{
+		"alias": alias,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, infoEndpoint, "info.getBlockchainID", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetBlockchainIDResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.BlockchainID, nil
+}
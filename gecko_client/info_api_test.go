@@ -58,3 +58,73 @@ func TestGetNodeId(t *testing.T) {
 	assert.Nil(t, err, "Error message should be nil")
 	assert.Equal(t, nodeId, "5mb46qkSBj81k9g9e4VFjGGSbaaSLFRzD")
 }
+
+func TestIsBootstrapped(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "isBootstrapped": true
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	isBootstrapped, err := client.InfoApi().IsBootstrapped("X")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, isBootstrapped)
+}
+
+func TestGetNetworkId(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "networkID": "12345"
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	networkId, err := client.InfoApi().GetNetworkId()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, uint32(12345), networkId)
+}
+
+func TestGetNetworkName(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "networkName": "local"
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	networkName, err := client.InfoApi().GetNetworkName()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, "local", networkName)
+}
+
+func TestGetNodeVersion(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "version": "avalanche/0.5.7"
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	version, err := client.InfoApi().GetNodeVersion()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, "avalanche/0.5.7", version)
+}
+
+func TestGetBlockchainId(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "blockchainID": "sV6o671RtkGBcno1FiaDbVcFv2sG5aVXMZYzKdP4VQAWmJQnM"
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	blockchainId, err := client.InfoApi().GetBlockchainId("X")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, "sV6o671RtkGBcno1FiaDbVcFv2sG5aVXMZYzKdP4VQAWmJQnM", blockchainId)
+}
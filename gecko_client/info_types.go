@@ -28,3 +28,53 @@ type GetNodeIDResponse struct {
 	Result NodeID	`json:"result"`
 	Id int	`json:"id"`
 }
+
+type BootstrapStatus struct {
+	IsBootstrapped bool	`json:"isBootstrapped"`
+}
+
+type IsBootstrappedResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result BootstrapStatus	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type NetworkID struct {
+	NetworkID string	`json:"networkID"`
+}
+
+type GetNetworkIDResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result NetworkID	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type NetworkName struct {
+	NetworkName string	`json:"networkName"`
+}
+
+type GetNetworkNameResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result NetworkName	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type NodeVersion struct {
+	Version string	`json:"version"`
+}
+
+type GetNodeVersionResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result NodeVersion	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type BlockchainID struct {
+	BlockchainID string	`json:"blockchainID"`
+}
+
+type GetBlockchainIDResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result BlockchainID	`json:"result"`
+	Id int	`json:"id"`
+}
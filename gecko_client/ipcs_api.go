@@ -0,0 +1,59 @@
+package gecko_client
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	ipcsEndpoint = "ext/ipcs"
+)
+
+// NOTE: Gecko only serves this API when launched with the "api-ipcs-enabled" CLI arg set to true
+type IpcsApi struct {
+	rpcRequester jsonRpcRequester
+	ctx          context.Context
+}
+
+/*
+Starts publishing a blockchain's accepted vertices/blocks over an IPC socket
+
+Args:
+	blockchainId: ID or alias of the blockchain to publish
+
+Returns:
+	The URL of the IPC socket that the blockchain is published to
+*/
+func (api IpcsApi) PublishBlockchain(blockchainId string) (string, error) {
+	params := map[string]interface{}{
+		"blockchainID": blockchainId,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, ipcsEndpoint, "ipcs.publishBlockchain", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response PublishBlockchainResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Url, nil
+}
+
+// Stops publishing a blockchain that was published with PublishBlockchain
+func (api IpcsApi) UnpublishBlockchain(blockchainId string) (bool, error) {
+	params := map[string]interface{}{
+		"blockchainID": blockchainId,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, ipcsEndpoint, "ipcs.unpublishBlockchain", params)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response UnpublishBlockchainResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return false, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Success, nil
+}
@@ -0,0 +1,34 @@
+package gecko_client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPublishBlockchain(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "url": "/tmp/1/sV6o671RtkGBcno1FiaDbVcFv2sG5aVXMZYzKdP4VQAWmJQnM"
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	url, err := client.IpcsApi().PublishBlockchain("X")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, "/tmp/1/sV6o671RtkGBcno1FiaDbVcFv2sG5aVXMZYzKdP4VQAWmJQnM", url)
+}
+
+func TestUnpublishBlockchain(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "success": true
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	success, err := client.IpcsApi().UnpublishBlockchain("X")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
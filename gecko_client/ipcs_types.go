@@ -0,0 +1,21 @@
+package gecko_client
+
+type PublishedBlockchain struct {
+	Url string `json:"url"`
+}
+
+type PublishBlockchainResponse struct {
+	JsonRpcVersion string              `json:"jsonrpc"`
+	Result         PublishedBlockchain `json:"result"`
+	Id             int                 `json:"id"`
+}
+
+type UnpublishBlockchain struct {
+	Success bool `json:"success"`
+}
+
+type UnpublishBlockchainResponse struct {
+	JsonRpcVersion string              `json:"jsonrpc"`
+	Result         UnpublishBlockchain `json:"result"`
+	Id             int                 `json:"id"`
+}
@@ -2,6 +2,7 @@ package gecko_client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/docker/go-connections/nat"
@@ -29,18 +30,19 @@ type JsonRpcRequest struct {
 type JsonRpcError struct {
 	Code int `json:"code"`
 	Message string `json:"message"`
-	Data interface{} `json: "data"`
+	Data interface{} `json:"data"`
 }
 
 type JsonRpcResponse struct {
 	JsonRpcVersion string             `json:"jsonrpc"`
-	Error JsonRpcError `json: "error"`
-	Result map[string]interface{} `json: "result"`
+	// Will be nil if the call succeeded
+	Error *JsonRpcError `json:"error"`
+	Result json.RawMessage `json:"result"`
 	Id             int                `json:"id"`
 }
 
 type jsonRpcRequester interface {
-	makeRpcRequest(endpoint string, method string, params map[string]interface{}) ([]byte, error)
+	makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error)
 }
 
 type geckoJsonRpcRequester struct {
@@ -60,7 +62,7 @@ func newGeckoJsonRpcRequester(ipAddr string, port nat.Port, requestTimeout time.
 }
 
 
-func (requester geckoJsonRpcRequester) makeRpcRequest(endpoint string, method string, params map[string]interface{}) ([]byte, error) {
+func (requester geckoJsonRpcRequester) makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error) {
 	// Either Golang or Ava have a very nasty & subtle behaviour where duplicated '//' in the URL is treated as GET, even if it's POST
 	// https://stackoverflow.com/questions/23463601/why-golang-treats-my-post-request-as-a-get-one
 	endpoint = strings.TrimLeft(endpoint, "/")
@@ -85,13 +87,17 @@ func (requester geckoJsonRpcRequester) makeRpcRequest(endpoint string, method st
 
 	logrus.Tracef("Making request to url: %v", url)
 	logrus.Tracef("Request body: %v", string(requestBodyBytes))
-	resp, err := http.Post(
-		url,
-		"application/json",
-		bytes.NewBuffer(requestBodyBytes),
-	)
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(requestBodyBytes))
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "Error occurred when making JSON RPC POST request to %v", url)
+		return nil, stacktrace.Propagate(err, "Error occurred building JSON RPC POST request to %v", url)
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	resp, err := requester.client.Do(httpRequest)
+	if err != nil {
+		return nil, stacktrace.Propagate(
+			&RpcTransportError{Url: url, Cause: err},
+			"Error occurred when making JSON RPC POST request to %v",
+			url)
 	}
 	defer resp.Body.Close()
 	statusCode := resp.StatusCode
@@ -99,23 +105,32 @@ func (requester geckoJsonRpcRequester) makeRpcRequest(endpoint string, method st
 
 	responseBodyBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "Error occurred when reading response body")
+		return nil, stacktrace.Propagate(&RpcTransportError{Url: url, Cause: err}, "Error occurred when reading response body")
 	}
 	logrus.Tracef("Response body: %v", string(responseBodyBytes))
 
-	if statusCode != 200 {
-		return nil, stacktrace.NewError(
-			"Received response with non-200 code '%v' and response body '%v'",
-			statusCode,
-			string(responseBodyBytes))
-	}
-
 	var response JsonRpcResponse
-	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
-		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	decodeErr := json.Unmarshal(responseBodyBytes, &response)
+	// Gecko may report JSON RPC errors with a non-200 code, in which case the JSON RPC error is the more useful one
+	if decodeErr == nil && response.Error != nil {
+		return nil, stacktrace.Propagate(
+			&RpcCallError{
+				Method:  method,
+				Code:    response.Error.Code,
+				Message: response.Error.Message,
+				Data:    response.Error.Data,
+			},
+			"RPC call failed")
+	}
+	if statusCode != http.StatusOK {
+		return nil, stacktrace.Propagate(
+			&RpcHttpStatusError{Url: url, StatusCode: statusCode, Body: string(responseBodyBytes)},
+			"Received response with non-200 code")
 	}
-	if response.Error.Code != 0 {
-		return nil, stacktrace.NewError("RPC call failed: %+v", response.Error)
+	if decodeErr != nil {
+		return nil, stacktrace.Propagate(
+			&RpcDecodeError{Body: string(responseBodyBytes), Cause: decodeErr},
+			"Error unmarshalling JSON response")
 	}
 	return responseBodyBytes, nil
 }
@@ -1,5 +1,19 @@
 package gecko_client
 
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/palantir/stacktrace"
+	"github.com/stretchr/testify/assert"
+)
+
 // A struct that implements the jsonRpcRequester interface but just returns the same thing every time
 type mockedJsonRpcRequester struct {
 	resultStr string
@@ -9,7 +23,62 @@ func newMockedJsonRpcRequester(resultStr string) *mockedJsonRpcRequester {
 	return &mockedJsonRpcRequester{resultStr: resultStr}
 }
 
-func (requester mockedJsonRpcRequester) makeRpcRequest(endpoint string, method string, params map[string]interface{}) ([]byte, error) {
+func (requester mockedJsonRpcRequester) makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error) {
 	bytes := []byte(requester.resultStr)
 	return bytes, nil
 }
+
+// ================= Real requester tests =====================
+func TestRequestCancelledByContext(t *testing.T) {
+	server, unblock := newHangingServer()
+	defer server.Close()
+	defer close(unblock)
+
+	requester := newRequesterForServer(t, server, requestTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	startTime := time.Now()
+	_, err := requester.makeRpcRequest(ctx, "ext/info", "info.getNodeID", map[string]interface{}{})
+	assert.Error(t, err)
+	assert.True(t, errors.Is(stacktrace.RootCause(err), context.DeadlineExceeded))
+	assert.True(t, time.Since(startTime) < requestTimeout, "Request wasn't cancelled when the context hit its deadline")
+}
+
+func TestRequestUsesClientTimeout(t *testing.T) {
+	server, unblock := newHangingServer()
+	defer server.Close()
+	defer close(unblock)
+
+	clientTimeout := 50 * time.Millisecond
+	requester := newRequesterForServer(t, server, clientTimeout)
+
+	startTime := time.Now()
+	_, err := requester.makeRpcRequest(context.Background(), "ext/info", "info.getNodeID", map[string]interface{}{})
+	assert.Error(t, err)
+	assert.True(t, time.Since(startTime) < requestTimeout, "Request didn't respect the requester's HTTP client timeout")
+}
+
+// Creates a server whose handlers hang until the returned channel is closed or the request is cancelled
+func newHangingServer() (*httptest.Server, chan struct{}) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-unblock:
+		case <-request.Context().Done():
+		}
+	}))
+	return server, unblock
+}
+
+func newRequesterForServer(t *testing.T, server *httptest.Server, timeout time.Duration) *geckoJsonRpcRequester {
+	serverUrl, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := nat.NewPort("tcp", serverUrl.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newGeckoJsonRpcRequester(serverUrl.Hostname(), port, timeout)
+}
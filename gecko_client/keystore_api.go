@@ -1,6 +1,7 @@
 package gecko_client
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/palantir/stacktrace"
 )
@@ -11,6 +12,7 @@ const (
 
 type KeystoreApi struct {
 	rpcRequester jsonRpcRequester
+	ctx          context.Context
 }
 
 // Creates a blockchain with the given parameters, returning the unsigned transaction identifier
@@ -19,7 +21,7 @@ func (api KeystoreApi) CreateUser(username string, password string) (bool, error
 		"username": username,
 		"password": password,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(keystoreEndpoint, "keystore.createUser", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, keystoreEndpoint, "keystore.createUser", params)
 	if err != nil {
 		return false, stacktrace.Propagate(err, "Error making request")
 	}
@@ -29,4 +31,80 @@ func (api KeystoreApi) CreateUser(username string, password string) (bool, error
 		return false, stacktrace.Propagate(err, "Error unmarshalling JSON response")
 	}
 	return response.Result.Success, nil
-}
\ No newline at end of file
+}
+
+// Lists the names of all the users in the node's keystore
+func (api KeystoreApi) ListUsers() ([]string, error) {
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, keystoreEndpoint, "keystore.listUsers", make(map[string]interface{}))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response ListUsersResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Users, nil
+}
+
+// Exports a user, including all their keys, as an opaque string which can be passed to ImportUser on another node
+func (api KeystoreApi) ExportUser(username string, password string) (string, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, keystoreEndpoint, "keystore.exportUser", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response ExportUserResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.User, nil
+}
+
+/*
+Imports a user previously exported with ExportUser
+
+Args:
+	username: Name to give the imported user
+	password: Password of the exported user
+	user: The exported user, as returned by ExportUser
+*/
+func (api KeystoreApi) ImportUser(username string, password string, user string) (bool, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+		"user":     user,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, keystoreEndpoint, "keystore.importUser", params)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response ImportUserResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return false, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Success, nil
+}
+
+// Deletes a user, and all their keys, from the node's keystore
+func (api KeystoreApi) DeleteUser(username string, password string) (bool, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, keystoreEndpoint, "keystore.deleteUser", params)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response DeleteUserResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return false, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Success, nil
+}
@@ -0,0 +1,62 @@
+package gecko_client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestListUsers(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "users": ["myUsername", "genesis"]
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	users, err := client.KeystoreApi().ListUsers()
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, []string{"myUsername", "genesis"}, users)
+}
+
+func TestExportUser(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "user": "7655a29df6fc2747b0874e1148b423b954a25fcdb1f170d0ec8eb196430f7001942ce55b02a83b1faf50a674b1e55bfc"
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	user, err := client.KeystoreApi().ExportUser("myUsername", "myPassword")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, "7655a29df6fc2747b0874e1148b423b954a25fcdb1f170d0ec8eb196430f7001942ce55b02a83b1faf50a674b1e55bfc", user)
+}
+
+func TestImportUser(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "success": true
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	success, err := client.KeystoreApi().ImportUser("myUsername", "myPassword", "0x7655a29df6fc2747b0874e1148b423b954a25fcdb1f170d0ec8eb196430f7001942ce55b02a83b1faf50a674b1e55bfc")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
+
+func TestDeleteUser(t *testing.T) {
+	resultStr := `{
+    "jsonrpc": "2.0",
+    "result": {
+        "success": true
+    },
+    "id": 1
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	success, err := client.KeystoreApi().DeleteUser("myUsername", "myPassword")
+	assert.Nil(t, err, "Error message should be nil")
+	assert.True(t, success)
+}
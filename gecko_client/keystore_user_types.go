@@ -8,4 +8,43 @@ type CreateUserResponse struct {
 	JsonRpcVersion string	`json:"jsonrpc"`
 	Result CreateUser	`json:"result"`
 	Id int	`json:"id"`
-}
\ No newline at end of file
+}
+type UserList struct {
+	Users []string `json:"users"`
+}
+
+type ListUsersResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result UserList	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type ExportedUser struct {
+	User string `json:"user"`
+}
+
+type ExportUserResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result ExportedUser	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type ImportUser struct {
+	Success bool `json:"success"`
+}
+
+type ImportUserResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result ImportUser	`json:"result"`
+	Id int	`json:"id"`
+}
+
+type DeleteUser struct {
+	Success bool `json:"success"`
+}
+
+type DeleteUserResponse struct {
+	JsonRpcVersion string	`json:"jsonrpc"`
+	Result DeleteUser	`json:"result"`
+	Id int	`json:"id"`
+}
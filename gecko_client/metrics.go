@@ -0,0 +1,291 @@
+package gecko_client
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+)
+
+type MetricType string
+
+const (
+	COUNTER   MetricType = "counter"
+	GAUGE     MetricType = "gauge"
+	HISTOGRAM MetricType = "histogram"
+	SUMMARY   MetricType = "summary"
+	UNTYPED   MetricType = "untyped"
+)
+
+// Suffixes of the samples that make up a single histogram or summary metric
+var histogramAndSummarySuffixes = []string{"_bucket", "_sum", "_count"}
+
+type MetricSample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Samples []MetricSample
+}
+
+// The metrics exposed by a Gecko node at a single point in time, grouped by metric family
+type Metrics struct {
+	families map[string]*MetricFamily
+}
+
+/*
+Parses metrics in the Prometheus text exposition format, which is what Gecko serves on its metrics endpoint
+
+Args:
+	reader: Reader over the text to parse
+*/
+func ParseMetrics(reader io.Reader) (*Metrics, error) {
+	metrics := &Metrics{
+		families: make(map[string]*MetricFamily),
+	}
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if err := metrics.parseCommentLine(line); err != nil {
+				return nil, stacktrace.Propagate(err, "Error parsing metrics comment on line %v", lineNum)
+			}
+			continue
+		}
+		sample, err := parseSampleLine(line)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Error parsing metrics sample on line %v", lineNum)
+		}
+		family := metrics.getFamilyForSample(sample.Name)
+		family.Samples = append(family.Samples, *sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "Error reading metrics")
+	}
+	return metrics, nil
+}
+
+// Gets the metric family with the given name, or false if the node doesn't expose it
+func (metrics Metrics) GetFamily(name string) (*MetricFamily, bool) {
+	family, found := metrics.families[name]
+	return family, found
+}
+
+// Gets the names of all the metric families, sorted
+func (metrics Metrics) GetFamilyNames() []string {
+	result := make([]string, 0, len(metrics.families))
+	for name := range metrics.families {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+/*
+Gets the value of a counter
+
+Args:
+	name: Name of the counter
+	labels: The exact labels of the sample to get (nil or empty for a counter without labels)
+*/
+func (metrics Metrics) GetCounter(name string, labels map[string]string) (float64, error) {
+	return metrics.getTypedValue(name, COUNTER, labels)
+}
+
+/*
+Gets the value of a gauge
+
+Args:
+	name: Name of the gauge
+	labels: The exact labels of the sample to get (nil or empty for a gauge without labels)
+*/
+func (metrics Metrics) GetGauge(name string, labels map[string]string) (float64, error) {
+	return metrics.getTypedValue(name, GAUGE, labels)
+}
+
+func (metrics Metrics) getTypedValue(name string, metricType MetricType, labels map[string]string) (float64, error) {
+	family, found := metrics.families[name]
+	if !found {
+		return 0, stacktrace.NewError("No metric with name %v", name)
+	}
+	if family.Type != metricType {
+		return 0, stacktrace.NewError("Metric %v is a %v, not a %v", name, family.Type, metricType)
+	}
+	for _, sample := range family.Samples {
+		if sample.Name == name && labelsEqual(sample.Labels, labels) {
+			return sample.Value, nil
+		}
+	}
+	return 0, stacktrace.NewError("Metric %v has no sample with labels %v", name, labels)
+}
+
+func (metrics *Metrics) parseCommentLine(line string) error {
+	fields := strings.Fields(strings.TrimPrefix(line, "#"))
+	// Any comment that isn't HELP or TYPE is free-form and carries no information
+	if len(fields) < 2 || (fields[0] != "HELP" && fields[0] != "TYPE") {
+		return nil
+	}
+	family := metrics.getOrCreateFamily(fields[1])
+	switch fields[0] {
+	case "HELP":
+		family.Help = strings.Join(fields[2:], " ")
+	case "TYPE":
+		if len(fields) != 3 {
+			return stacktrace.NewError("Expected TYPE comment to have a metric name and a type but got '%v'", line)
+		}
+		metricType := MetricType(fields[2])
+		switch metricType {
+		case COUNTER, GAUGE, HISTOGRAM, SUMMARY, UNTYPED:
+			family.Type = metricType
+		default:
+			return stacktrace.NewError("Unrecognized metric type '%v'", fields[2])
+		}
+	}
+	return nil
+}
+
+func (metrics *Metrics) getOrCreateFamily(name string) *MetricFamily {
+	family, found := metrics.families[name]
+	if !found {
+		family = &MetricFamily{
+			Name:    name,
+			Type:    UNTYPED,
+			Samples: make([]MetricSample, 0),
+		}
+		metrics.families[name] = family
+	}
+	return family
+}
+
+// Histograms and summaries are exposed as several samples whose names are the family name plus a suffix
+func (metrics *Metrics) getFamilyForSample(sampleName string) *MetricFamily {
+	if family, found := metrics.families[sampleName]; found {
+		return family
+	}
+	for _, suffix := range histogramAndSummarySuffixes {
+		if !strings.HasSuffix(sampleName, suffix) {
+			continue
+		}
+		family, found := metrics.families[strings.TrimSuffix(sampleName, suffix)]
+		if found && (family.Type == HISTOGRAM || family.Type == SUMMARY) {
+			return family
+		}
+	}
+	return metrics.getOrCreateFamily(sampleName)
+}
+
+// Parses a line of the form 'name{label="value",...} value [timestamp]'
+func parseSampleLine(line string) (*MetricSample, error) {
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd <= 0 {
+		return nil, stacktrace.NewError("Expected a metric name followed by a value but got '%v'", line)
+	}
+	name := line[:nameEnd]
+	remainder := line[nameEnd:]
+
+	labels := make(map[string]string)
+	if strings.HasPrefix(remainder, "{") {
+		parsedLabels, afterLabels, err := parseLabels(remainder[1:])
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Error parsing labels of metric %v", name)
+		}
+		labels = parsedLabels
+		remainder = afterLabels
+	}
+
+	// The timestamp is optional, and not something tests have any use for
+	valueFields := strings.Fields(remainder)
+	if len(valueFields) < 1 || len(valueFields) > 2 {
+		return nil, stacktrace.NewError("Expected a value and an optional timestamp after metric %v but got '%v'", name, remainder)
+	}
+	value, err := strconv.ParseFloat(valueFields[0], 64)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not parse value '%v' of metric %v", valueFields[0], name)
+	}
+	return &MetricSample{
+		Name:   name,
+		Labels: labels,
+		Value:  value,
+	}, nil
+}
+
+// Parses the labels after the opening brace, returning the labels and whatever follows the closing brace
+func parseLabels(text string) (map[string]string, string, error) {
+	labels := make(map[string]string)
+	remainder := text
+	for {
+		remainder = strings.TrimLeft(remainder, " \t")
+		if strings.HasPrefix(remainder, "}") {
+			return labels, remainder[1:], nil
+		}
+		equalsIdx := strings.Index(remainder, "=")
+		if equalsIdx <= 0 {
+			return nil, "", stacktrace.NewError("Expected a label name followed by '=' but got '%v'", remainder)
+		}
+		labelName := strings.TrimSpace(remainder[:equalsIdx])
+		remainder = strings.TrimLeft(remainder[equalsIdx+1:], " \t")
+		if !strings.HasPrefix(remainder, "\"") {
+			return nil, "", stacktrace.NewError("Expected a quoted value for label %v but got '%v'", labelName, remainder)
+		}
+		labelValue, afterValue, err := parseQuotedLabelValue(remainder[1:])
+		if err != nil {
+			return nil, "", stacktrace.Propagate(err, "Error parsing value of label %v", labelName)
+		}
+		labels[labelName] = labelValue
+		remainder = strings.TrimLeft(afterValue, " \t")
+		if strings.HasPrefix(remainder, ",") {
+			remainder = remainder[1:]
+		}
+	}
+}
+
+// Parses a label value after its opening quote, undoing the escaping that the exposition format applies
+func parseQuotedLabelValue(text string) (string, string, error) {
+	var builder strings.Builder
+	for i := 0; i < len(text); i++ {
+		char := text[i]
+		switch char {
+		case '"':
+			return builder.String(), text[i+1:], nil
+		case '\\':
+			if i+1 >= len(text) {
+				return "", "", stacktrace.NewError("Label value ends in an incomplete escape sequence")
+			}
+			i++
+			switch text[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			default:
+				builder.WriteByte(text[i])
+			}
+		default:
+			builder.WriteByte(char)
+		}
+	}
+	return "", "", stacktrace.NewError("Label value is missing its closing quote")
+}
+
+func labelsEqual(first map[string]string, second map[string]string) bool {
+	if len(first) != len(second) {
+		return false
+	}
+	for key, value := range first {
+		if otherValue, found := second[key]; !found || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
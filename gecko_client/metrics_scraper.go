@@ -0,0 +1,64 @@
+package gecko_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	metricsEndpoint = "ext/metrics"
+)
+
+/*
+Scrapes the Prometheus metrics that a Gecko node exposes. This is separate from GeckoClient because the metrics endpoint
+is plain HTTP rather than JSON RPC.
+*/
+type MetricsScraper struct {
+	client http.Client
+	url    string
+	ctx    context.Context
+}
+
+func NewMetricsScraper(ipAddr string, port nat.Port) *MetricsScraper {
+	return &MetricsScraper{
+		client: http.Client{
+			Timeout: requestTimeout,
+		},
+		url: fmt.Sprintf("http://%v:%v/%v", ipAddr, port.Int(), metricsEndpoint),
+		ctx: context.Background(),
+	}
+}
+
+/*
+Returns a copy of this scraper whose scrapes are all bound to the given context
+*/
+func (scraper MetricsScraper) WithContext(ctx context.Context) *MetricsScraper {
+	scraper.ctx = ctx
+	return &scraper
+}
+
+// Fetches and parses the node's current metrics
+func (scraper MetricsScraper) Scrape() (*Metrics, error) {
+	request, err := http.NewRequestWithContext(scraper.ctx, http.MethodGet, scraper.url, nil)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error building metrics request")
+	}
+	response, err := scraper.client.Do(request)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error scraping metrics from %v", scraper.url)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, stacktrace.NewError("Scraping metrics from %v returned status %v", scraper.url, response.StatusCode)
+	}
+	metrics, err := ParseMetrics(response.Body)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error parsing metrics from %v", scraper.url)
+	}
+	return metrics, nil
+}
@@ -0,0 +1,110 @@
+package gecko_client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const metricsText = `# HELP gecko_X_vtx_processing Number of currently processing vertices
+# TYPE gecko_X_vtx_processing gauge
+gecko_X_vtx_processing 4
+# HELP gecko_X_vtx_accepted Number of vertices accepted
+# TYPE gecko_X_vtx_accepted counter
+gecko_X_vtx_accepted 12 1593000000000
+# HELP gecko_requests_total Requests made, by method
+# TYPE gecko_requests_total counter
+gecko_requests_total{method="get",chain="X"} 3
+gecko_requests_total{method="put",chain="X"} 7
+# HELP gecko_X_vtx_accept_latency Latency of accepting a vertex
+# TYPE gecko_X_vtx_accept_latency histogram
+gecko_X_vtx_accept_latency_bucket{le="100"} 1
+gecko_X_vtx_accept_latency_bucket{le="+Inf"} 2
+gecko_X_vtx_accept_latency_sum 250
+gecko_X_vtx_accept_latency_count 2
+# Free-form comments are ignored
+go_goroutines{note="a \"quoted\" value\\path"} 42
+`
+
+func TestParseGaugeAndCounter(t *testing.T) {
+	metrics, err := ParseMetrics(strings.NewReader(metricsText))
+	assert.Nil(t, err, "Error message should be nil")
+
+	processing, err := metrics.GetGauge("gecko_X_vtx_processing", nil)
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, 4.0, processing)
+
+	accepted, err := metrics.GetCounter("gecko_X_vtx_accepted", nil)
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, 12.0, accepted)
+
+	family, found := metrics.GetFamily("gecko_X_vtx_accepted")
+	assert.True(t, found)
+	assert.Equal(t, "Number of vertices accepted", family.Help)
+
+	_, err = metrics.GetCounter("gecko_X_vtx_processing", nil)
+	assert.NotNil(t, err, "Getting a gauge as a counter should fail")
+}
+
+func TestParseLabelledSamples(t *testing.T) {
+	metrics, err := ParseMetrics(strings.NewReader(metricsText))
+	assert.Nil(t, err, "Error message should be nil")
+
+	puts, err := metrics.GetCounter("gecko_requests_total", map[string]string{"method": "put", "chain": "X"})
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, 7.0, puts)
+
+	_, err = metrics.GetCounter("gecko_requests_total", nil)
+	assert.NotNil(t, err, "Labels must match exactly")
+
+	untyped, found := metrics.GetFamily("go_goroutines")
+	assert.True(t, found)
+	assert.Equal(t, UNTYPED, untyped.Type)
+	assert.Equal(t, `a "quoted" value\path`, untyped.Samples[0].Labels["note"])
+}
+
+func TestParseHistogramGroupsSamples(t *testing.T) {
+	metrics, err := ParseMetrics(strings.NewReader(metricsText))
+	assert.Nil(t, err, "Error message should be nil")
+
+	family, found := metrics.GetFamily("gecko_X_vtx_accept_latency")
+	assert.True(t, found)
+	assert.Equal(t, HISTOGRAM, family.Type)
+	assert.Equal(t, 4, len(family.Samples))
+
+	_, found = metrics.GetFamily("gecko_X_vtx_accept_latency_count")
+	assert.False(t, found, "Histogram samples should not get their own family")
+	assert.Equal(t, 5, len(metrics.GetFamilyNames()))
+}
+
+func TestParseMalformedMetrics(t *testing.T) {
+	_, err := ParseMetrics(strings.NewReader("gecko_X_vtx_processing notANumber"))
+	assert.NotNil(t, err)
+
+	_, err = ParseMetrics(strings.NewReader(`gecko_requests_total{method="get} 3`))
+	assert.NotNil(t, err)
+}
+
+func TestScrapeMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/"+metricsEndpoint, request.URL.Path)
+		fmt.Fprint(writer, metricsText)
+	}))
+	defer server.Close()
+
+	scraper := MetricsScraper{
+		client: *server.Client(),
+		url:    server.URL + "/" + metricsEndpoint,
+		ctx:    context.Background(),
+	}
+	metrics, err := scraper.Scrape()
+	assert.Nil(t, err, "Error message should be nil")
+	processing, err := metrics.GetGauge("gecko_X_vtx_processing", nil)
+	assert.Nil(t, err, "Error message should be nil")
+	assert.Equal(t, 4.0, processing)
+}
@@ -49,10 +49,10 @@ type GetBlockchainStatusResponse struct {
 }
 
 type Blockchain struct {
-	Id string `json: "id"`
-	Name string `json: "name"`
-	SubnetID string `json: "subnetID"`
-	VmID string `json: "vmID"`
+	Id string `json:"id"`
+	Name string `json:"name"`
+	SubnetID string `json:"subnetID"`
+	VmID string `json:"vmID"`
 }
 
 type GetBlockchainsResponse struct {
@@ -0,0 +1,141 @@
+package gecko_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// JSON RPC methods which don't change node state, and so are always safe to retry
+var readOnlyMethods = map[string]bool{
+	"health.getLiveness":            true,
+	"info.peers":                    true,
+	"info.getNodeID":                true,
+	"info.isBootstrapped":           true,
+	"info.getNetworkID":             true,
+	"info.getNetworkName":           true,
+	"info.getNodeVersion":           true,
+	"info.getBlockchainID":          true,
+	"admin.peers":                   true,
+	"keystore.listUsers":            true,
+	"keystore.exportUser":           true,
+	"platform.getBlockchainStatus":  true,
+	"platform.exportKey":            true,
+	"platform.getAccount":           true,
+	"platform.listAccounts":         true,
+	"platform.getCurrentValidators": true,
+	"platform.getPendingValidators": true,
+	"platform.sampleValidators":     true,
+	"platform.getSubnets":           true,
+	"platform.validatedBy":          true,
+	"platform.validates":            true,
+	"platform.getBlockchains":       true,
+	"avm.getTxStatus":               true,
+	"avm.getBalance":                true,
+	"avm.getAssetDescription":       true,
+	"avm.getAllBalances":            true,
+	"avm.getUTXOs":                  true,
+	"avm.listAddresses":             true,
+	"avm.exportKey":                 true,
+	"avm.getTx":                     true,
+	"avm.buildGenesis":              true,
+}
+
+// ================ Retry Policy ====================
+type RetryPolicy struct {
+	maxAttempts      int
+	backoff          poller.Backoff
+	isRetryableError func(err error) bool
+	optInMethods     map[string]bool
+}
+
+/*
+Args:
+	maxAttempts: The maximum number of times a request will be made, including the first attempt
+	backoff: Determines how long to wait between attempts
+	isRetryableError: Classifies which request errors are worth retrying (see IsTransientRpcError for a sensible default)
+	optInMethods: State-changing JSON RPC methods (e.g. "avm.issueTx") which should be retried in addition to the
+		read-only methods, which are always retried
+*/
+func NewRetryPolicy(
+	maxAttempts int,
+	backoff poller.Backoff,
+	isRetryableError func(err error) bool,
+	optInMethods []string) *RetryPolicy {
+	optInMethodsSet := make(map[string]bool)
+	for _, method := range optInMethods {
+		optInMethodsSet[method] = true
+	}
+	return &RetryPolicy{
+		maxAttempts:      maxAttempts,
+		backoff:          backoff,
+		isRetryableError: isRetryableError,
+		optInMethods:     optInMethodsSet,
+	}
+}
+
+func (policy RetryPolicy) isRetryableMethod(method string) bool {
+	return readOnlyMethods[method] || policy.optInMethods[method]
+}
+
+/*
+Default retryable error classifier, which retries errors that are typical of a node which is starting up or
+temporarily unreachable: connection failures and 5xx responses. Errors from the request's context being cancelled or
+hitting its deadline are never retryable.
+*/
+func IsTransientRpcError(err error) bool {
+	rootCause := stacktrace.RootCause(err)
+	if errors.Is(rootCause, context.Canceled) || errors.Is(rootCause, context.DeadlineExceeded) {
+		return false
+	}
+	var transportErr *RpcTransportError
+	if errors.As(rootCause, &transportErr) {
+		return true
+	}
+	var statusErr *RpcHttpStatusError
+	if errors.As(rootCause, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// ================ Retrying Requester ====================
+// Decorates another requester, retrying failed requests according to a retry policy
+type retryingJsonRpcRequester struct {
+	wrapped jsonRpcRequester
+	policy  RetryPolicy
+}
+
+func (requester retryingJsonRpcRequester) makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error) {
+	if !requester.policy.isRetryableMethod(method) {
+		return requester.wrapped.makeRpcRequest(ctx, endpoint, method, params)
+	}
+
+	for attempt := 1; ; attempt++ {
+		responseBodyBytes, err := requester.wrapped.makeRpcRequest(ctx, endpoint, method, params)
+		if err == nil {
+			return responseBodyBytes, nil
+		}
+		if !requester.policy.isRetryableError(err) {
+			return nil, stacktrace.Propagate(err, "Request for method '%v' failed with a non-retryable error on attempt %v", method, attempt)
+		}
+		if attempt >= requester.policy.maxAttempts {
+			return nil, stacktrace.Propagate(err, "Request for method '%v' failed on all %v attempts", method, attempt)
+		}
+
+		interval := requester.policy.backoff.GetInterval(attempt)
+		logrus.Debugf("Request for method '%v' failed on attempt %v; retrying in %v: %v", method, attempt, interval, err)
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, stacktrace.Propagate(err, "Context was done while waiting to retry request for method '%v'", method)
+		case <-timer.C:
+		}
+	}
+}
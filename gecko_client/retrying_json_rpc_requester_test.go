@@ -0,0 +1,121 @@
+package gecko_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/poller"
+	"github.com/palantir/stacktrace"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testMaxAttempts = 3
+)
+
+// A requester that fails with each of the given errors in turn, before returning the result string on every call after
+type flakyJsonRpcRequester struct {
+	errs      []error
+	resultStr string
+	calls     *int
+}
+
+func newFlakyJsonRpcRequester(resultStr string, errs ...error) *flakyJsonRpcRequester {
+	calls := 0
+	return &flakyJsonRpcRequester{
+		errs:      errs,
+		resultStr: resultStr,
+		calls:     &calls,
+	}
+}
+
+func (requester flakyJsonRpcRequester) makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error) {
+	call := *requester.calls
+	*requester.calls++
+	if call < len(requester.errs) {
+		return nil, stacktrace.Propagate(requester.errs[call], "Flaky request failed")
+	}
+	return []byte(requester.resultStr), nil
+}
+
+func newTestRetryPolicy(optInMethods ...string) *RetryPolicy {
+	return NewRetryPolicy(testMaxAttempts, poller.NewConstantBackoff(time.Millisecond), IsTransientRpcError, optInMethods)
+}
+
+func TestRetriesTransientErrors(t *testing.T) {
+	requester := newFlakyJsonRpcRequester(
+		`{"jsonrpc":"2.0","result":{"status":"Accepted"},"id":1}`,
+		&RpcTransportError{Url: "http://1.2.3.4:9650/ext/bc/X", Cause: errors.New("connection refused")},
+		&RpcHttpStatusError{StatusCode: http.StatusServiceUnavailable})
+	client := clientFromRequester(requester).WithRetryPolicy(newTestRetryPolicy())
+
+	status, err := client.XChainApi().GetTxStatus("txId")
+	assert.NoError(t, err)
+	assert.Equal(t, "Accepted", status)
+	assert.Equal(t, 3, *requester.calls)
+}
+
+func TestGivesUpAfterMaxAttempts(t *testing.T) {
+	transientErr := &RpcTransportError{Cause: errors.New("connection refused")}
+	requester := newFlakyJsonRpcRequester("", transientErr, transientErr, transientErr, transientErr)
+	client := clientFromRequester(requester).WithRetryPolicy(newTestRetryPolicy())
+
+	_, err := client.InfoApi().GetPeers()
+	var transportErr *RpcTransportError
+	assert.True(t, AsRpcError(err, &transportErr))
+	assert.Equal(t, testMaxAttempts, *requester.calls)
+}
+
+func TestDoesNotRetryNonTransientErrors(t *testing.T) {
+	requester := newFlakyJsonRpcRequester(
+		`{"jsonrpc":"2.0","result":{"validators":[]},"id":1}`,
+		&RpcCallError{Code: -32000, Message: "invalid subnet ID"},
+		&RpcHttpStatusError{StatusCode: http.StatusNotFound})
+	client := clientFromRequester(requester).WithRetryPolicy(newTestRetryPolicy())
+
+	_, err := client.PChainApi().GetCurrentValidators(nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, *requester.calls)
+}
+
+func TestStateChangingMethodsNotRetriedByDefault(t *testing.T) {
+	requester := newFlakyJsonRpcRequester(
+		`{"jsonrpc":"2.0","result":{"txID":"txId"},"id":1}`,
+		&RpcTransportError{Cause: errors.New("connection refused")})
+	client := clientFromRequester(requester).WithRetryPolicy(newTestRetryPolicy())
+
+	_, err := client.XChainApi().IssueTx("tx")
+	assert.Error(t, err)
+	assert.Equal(t, 1, *requester.calls)
+}
+
+func TestStateChangingMethodsRetriedWhenOptedIn(t *testing.T) {
+	requester := newFlakyJsonRpcRequester(
+		`{"jsonrpc":"2.0","result":{"txID":"txId"},"id":1}`,
+		&RpcTransportError{Cause: errors.New("connection refused")})
+	client := clientFromRequester(requester).WithRetryPolicy(newTestRetryPolicy("avm.issueTx"))
+
+	txId, err := client.XChainApi().IssueTx("tx")
+	assert.NoError(t, err)
+	assert.Equal(t, "txId", txId)
+	assert.Equal(t, 2, *requester.calls)
+}
+
+func TestContextErrorsNotRetried(t *testing.T) {
+	assert.False(t, IsTransientRpcError(&RpcTransportError{Cause: context.DeadlineExceeded}))
+	assert.False(t, IsTransientRpcError(&RpcTransportError{Cause: context.Canceled}))
+	assert.True(t, IsTransientRpcError(&RpcHttpStatusError{StatusCode: http.StatusBadGateway}))
+	assert.False(t, IsTransientRpcError(&RpcHttpStatusError{StatusCode: http.StatusBadRequest}))
+}
+
+func TestNilRetryPolicyLeavesClientUnchanged(t *testing.T) {
+	requester := newFlakyJsonRpcRequester("", &RpcTransportError{Cause: errors.New("connection refused")})
+	client := clientFromRequester(requester).WithRetryPolicy(nil)
+
+	_, err := client.InfoApi().GetPeers()
+	assert.Error(t, err)
+	assert.Equal(t, 1, *requester.calls)
+}
@@ -0,0 +1,122 @@
+package gecko_client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/palantir/stacktrace"
+)
+
+/*
+Sentinel classifications of well-known Gecko failures. An RpcCallError will match these with errors.Is when its
+message indicates the corresponding failure.
+*/
+var (
+	ErrNoImportInputs    = errors.New("no import inputs")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrUnknownUser       = errors.New("unknown user")
+	ErrUserAlreadyExists = errors.New("user already exists")
+
+	// The node doesn't serve the called method, e.g. because it's running a Gecko version from before the method existed
+	ErrMethodNotFound = errors.New("method not found")
+)
+
+// The JSON RPC 2.0 error code for a method that doesn't exist
+const jsonRpcMethodNotFoundCode = -32601
+
+// Substrings of Gecko's JSON RPC error messages which identify each of the well-known failures
+var rpcErrorClassifications = map[error][]string{
+	ErrNoImportInputs:    {"no import inputs"},
+	ErrInsufficientFunds: {"insufficient funds"},
+	ErrUnknownUser:       {"user doesn't exist", "user does not exist"},
+	ErrUserAlreadyExists: {"user already exists"},
+	// Gecko's RPC server reports unknown methods with a generic server error code, so only the message identifies them
+	ErrMethodNotFound: {"can't find method", "can't find service"},
+}
+
+// ================ Transport ====================
+// Returned when an HTTP request to Gecko couldn't be made, or its response couldn't be read
+type RpcTransportError struct {
+	Url   string
+	Cause error
+}
+
+func (err *RpcTransportError) Error() string {
+	return fmt.Sprintf("Error making JSON RPC request to %v: %v", err.Url, err.Cause)
+}
+
+func (err *RpcTransportError) Unwrap() error {
+	return err.Cause
+}
+
+// ================ HTTP Status ====================
+// Returned when Gecko responds with a non-200 status code and no JSON RPC error in the body
+type RpcHttpStatusError struct {
+	Url        string
+	StatusCode int
+	Body       string
+}
+
+func (err *RpcHttpStatusError) Error() string {
+	return fmt.Sprintf("Received response with non-200 code '%v' from %v and response body '%v'", err.StatusCode, err.Url, err.Body)
+}
+
+// ================ JSON RPC Call ====================
+// Returned when Gecko responds with a JSON RPC error object
+type RpcCallError struct {
+	Method  string
+	Code    int
+	Message string
+	Data    interface{}
+}
+
+func (err *RpcCallError) Error() string {
+	return fmt.Sprintf("JSON RPC call '%v' failed with code %v: %v (data: %v)", err.Method, err.Code, err.Message, err.Data)
+}
+
+// Allows errors.Is to match the error against the sentinel failure classifications
+func (err *RpcCallError) Is(target error) bool {
+	if target == ErrMethodNotFound && err.Code == jsonRpcMethodNotFoundCode {
+		return true
+	}
+	substrings, found := rpcErrorClassifications[target]
+	if !found {
+		return false
+	}
+	for _, substring := range substrings {
+		if strings.Contains(err.Message, substring) {
+			return true
+		}
+	}
+	return false
+}
+
+// ================ Decode ====================
+// Returned when Gecko's response body couldn't be parsed as a JSON RPC response
+type RpcDecodeError struct {
+	Body  string
+	Cause error
+}
+
+func (err *RpcDecodeError) Error() string {
+	return fmt.Sprintf("Error decoding JSON RPC response body '%v': %v", err.Body, err.Cause)
+}
+
+func (err *RpcDecodeError) Unwrap() error {
+	return err.Cause
+}
+
+// ================ Inspection ====================
+/*
+Reports whether the error returned by a Gecko client call matches the target (e.g. ErrNoImportInputs). The client wraps
+its errors in stacktraces, which errors.Is can't see through on its own.
+*/
+func IsRpcError(err error, target error) bool {
+	return errors.Is(stacktrace.RootCause(err), target)
+}
+
+// Like errors.As, but for errors returned by a Gecko client call (which are wrapped in stacktraces)
+func AsRpcError(err error, target interface{}) bool {
+	return errors.As(stacktrace.RootCause(err), target)
+}
@@ -0,0 +1,103 @@
+package gecko_client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRpcCallErrorParsed(t *testing.T) {
+	responseBody := `{"jsonrpc":"2.0","error":{"code":-32000,"message":"problem issuing transaction: no import inputs","data":{"detail":"foo"}},"id":1}`
+	_, err := makeRequestAgainstServer(t, http.StatusOK, responseBody)
+
+	var callErr *RpcCallError
+	if !assert.True(t, AsRpcError(err, &callErr)) {
+		return
+	}
+	assert.Equal(t, "platform.importAVA", callErr.Method)
+	assert.Equal(t, -32000, callErr.Code)
+	assert.Equal(t, "problem issuing transaction: no import inputs", callErr.Message)
+	assert.Equal(t, map[string]interface{}{"detail": "foo"}, callErr.Data)
+	assert.True(t, IsRpcError(err, ErrNoImportInputs))
+	assert.False(t, IsRpcError(err, ErrInsufficientFunds))
+}
+
+func TestRpcCallErrorWithNon200Code(t *testing.T) {
+	responseBody := `{"jsonrpc":"2.0","error":{"code":-32000,"message":"user doesn't exist","data":null},"id":1}`
+	_, err := makeRequestAgainstServer(t, http.StatusBadRequest, responseBody)
+
+	var callErr *RpcCallError
+	assert.True(t, AsRpcError(err, &callErr))
+	assert.True(t, IsRpcError(err, ErrUnknownUser))
+}
+
+func TestRpcHttpStatusError(t *testing.T) {
+	_, err := makeRequestAgainstServer(t, http.StatusNotFound, "404 page not found")
+
+	var statusErr *RpcHttpStatusError
+	if !assert.True(t, AsRpcError(err, &statusErr)) {
+		return
+	}
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, "404 page not found", statusErr.Body)
+}
+
+func TestRpcDecodeError(t *testing.T) {
+	_, err := makeRequestAgainstServer(t, http.StatusOK, "not json")
+
+	var decodeErr *RpcDecodeError
+	if !assert.True(t, AsRpcError(err, &decodeErr)) {
+		return
+	}
+	assert.Equal(t, "not json", decodeErr.Body)
+}
+
+func TestRpcTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	requester := newRequesterForServer(t, server, requestTimeout)
+	// Closing the server up front guarantees the connection will be refused
+	server.Close()
+
+	_, err := requester.makeRpcRequest(context.Background(), pchainEndpoint, "platform.importAVA", map[string]interface{}{})
+	var transportErr *RpcTransportError
+	assert.True(t, AsRpcError(err, &transportErr))
+}
+
+func TestSuccessfulResponseHasNoError(t *testing.T) {
+	responseBody := `{"jsonrpc":"2.0","result":{"txID":"abc"},"id":1}`
+	responseBodyBytes, err := makeRequestAgainstServer(t, http.StatusOK, responseBody)
+	assert.NoError(t, err)
+	assert.Equal(t, responseBody, string(responseBodyBytes))
+}
+
+func TestRpcErrorClassifications(t *testing.T) {
+	insufficientFundsErr := &RpcCallError{Message: "couldn't issue tx: insufficient funds"}
+	assert.True(t, errors.Is(insufficientFundsErr, ErrInsufficientFunds))
+	assert.False(t, errors.Is(insufficientFundsErr, ErrNoImportInputs))
+
+	alreadyExistsErr := &RpcCallError{Message: "user already exists: genesis"}
+	assert.True(t, errors.Is(alreadyExistsErr, ErrUserAlreadyExists))
+	assert.False(t, errors.Is(alreadyExistsErr, ErrUnknownUser))
+
+	// As Gecko's RPC server reports a method it doesn't have
+	unknownMethodErr := &RpcCallError{Code: -32000, Message: `rpc: can't find method "info.isBootstrapped"`}
+	assert.True(t, errors.Is(unknownMethodErr, ErrMethodNotFound))
+	assert.True(t, errors.Is(&RpcCallError{Code: -32601, Message: "Method not found"}, ErrMethodNotFound))
+	assert.False(t, errors.Is(insufficientFundsErr, ErrMethodNotFound))
+}
+
+// Makes an importAVA request against a server which always responds with the given status code and body
+func makeRequestAgainstServer(t *testing.T, statusCode int, responseBody string) ([]byte, error) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(statusCode)
+		writer.Write([]byte(responseBody))
+	}))
+	defer server.Close()
+
+	requester := newRequesterForServer(t, server, requestTimeout)
+	return requester.makeRpcRequest(context.Background(), pchainEndpoint, "platform.importAVA", map[string]interface{}{})
+}
@@ -0,0 +1,225 @@
+package gecko_client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+// ================ Error Kinds ====================
+// Which of the Gecko client's error types a recorded request failed with, so the error can be replayed with its type
+type RpcErrorKind string
+
+const (
+	RPC_ERROR_KIND_CALL        RpcErrorKind = "call"
+	RPC_ERROR_KIND_TRANSPORT   RpcErrorKind = "transport"
+	RPC_ERROR_KIND_HTTP_STATUS RpcErrorKind = "httpStatus"
+	RPC_ERROR_KIND_DECODE      RpcErrorKind = "decode"
+
+	// Any other failure (e.g. the request's context being done), which is replayed as an untyped error
+	RPC_ERROR_KIND_OTHER RpcErrorKind = "other"
+)
+
+// ================ Recording ====================
+// A single JSON RPC request made by the Gecko client and its outcome, serialized as one line of a JSONL recording
+type RpcRecording struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// ID of the service in the test network that the request was made to
+	ServiceId string          `json:"serviceId"`
+	Endpoint  string          `json:"endpoint"`
+	Method    string          `json:"method"`
+	Params    json.RawMessage `json:"params"`
+
+	// Nanoseconds between the request being sent and the response (or error) being received
+	Latency time.Duration `json:"latency"`
+
+	// Empty if the request failed
+	ResponseBody string `json:"responseBody,omitempty"`
+
+	// Set if the request failed because Gecko returned a JSON RPC error, so the error can be replayed with its type
+	CallError *JsonRpcError `json:"callError,omitempty"`
+
+	// Set if the request failed for any reason
+	Error string `json:"error,omitempty"`
+
+	// Set if the request failed; empty in recordings made before error kinds were recorded, which replay as untyped
+	ErrorKind RpcErrorKind `json:"errorKind,omitempty"`
+
+	// Set if the request failed with a transport or HTTP status error
+	ErrorUrl string `json:"errorUrl,omitempty"`
+
+	// Set if the request failed with an HTTP status error
+	ErrorStatusCode int `json:"errorStatusCode,omitempty"`
+
+	// Set if the request failed with an HTTP status or decode error, to the body Gecko responded with
+	ErrorBody string `json:"errorBody,omitempty"`
+
+	// Set if the request failed with a transport or decode error, to the message of the underlying cause
+	ErrorCause string `json:"errorCause,omitempty"`
+}
+
+// ================ Recorder ====================
+/*
+Writes recordings of JSON RPC requests as JSONL, and can be shared between the clients for all the services in a network
+*/
+type RpcRecorder struct {
+	mutex  *sync.Mutex
+	writer io.Writer
+
+	// Only set if the recorder owns the underlying file
+	file *os.File
+}
+
+func NewRpcRecorder(writer io.Writer) *RpcRecorder {
+	return &RpcRecorder{
+		mutex:  &sync.Mutex{},
+		writer: writer,
+	}
+}
+
+// Creates a recorder that writes to a new file at the given filepath, which must be closed when recording is finished
+func NewFileRpcRecorder(filepath string) (*RpcRecorder, error) {
+	file, err := os.Create(filepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not create RPC recording file at %v", filepath)
+	}
+	recorder := NewRpcRecorder(file)
+	recorder.file = file
+	return recorder, nil
+}
+
+func (recorder *RpcRecorder) record(recording RpcRecording) error {
+	recordingBytes, err := json.Marshal(recording)
+	if err != nil {
+		return stacktrace.Propagate(err, "Could not serialize RPC recording")
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	if _, err := recorder.writer.Write(append(recordingBytes, '\n')); err != nil {
+		return stacktrace.Propagate(err, "Could not write RPC recording")
+	}
+	return nil
+}
+
+func (recorder *RpcRecorder) Close() error {
+	if recorder.file == nil {
+		return nil
+	}
+	if err := recorder.file.Close(); err != nil {
+		return stacktrace.Propagate(err, "Could not close RPC recording file")
+	}
+	return nil
+}
+
+// Reads back recordings written by an RpcRecorder
+func LoadRpcRecordings(reader io.Reader) ([]RpcRecording, error) {
+	recordings := []RpcRecording{}
+	scanner := bufio.NewScanner(reader)
+	// Response bodies (e.g. the validators list) can easily be longer than the scanner's default max line length
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var recording RpcRecording
+		if err := json.Unmarshal(scanner.Bytes(), &recording); err != nil {
+			return nil, stacktrace.Propagate(err, "Could not parse RPC recording on line %v", lineNumber)
+		}
+		recordings = append(recordings, recording)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "Error reading RPC recordings")
+	}
+	return recordings, nil
+}
+
+func LoadRpcRecordingsFile(filepath string) ([]RpcRecording, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not open RPC recording file at %v", filepath)
+	}
+	defer file.Close()
+	recordings, err := LoadRpcRecordings(file)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not load RPC recordings from %v", filepath)
+	}
+	return recordings, nil
+}
+
+// ================ Recording Requester ====================
+// Decorates another requester, recording every request it makes
+type recordingJsonRpcRequester struct {
+	wrapped   jsonRpcRequester
+	recorder  *RpcRecorder
+	serviceId string
+}
+
+func (requester recordingJsonRpcRequester) makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error) {
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not serialize params '%v' for recording", params)
+	}
+
+	startTime := time.Now()
+	responseBodyBytes, requestErr := requester.wrapped.makeRpcRequest(ctx, endpoint, method, params)
+	recording := RpcRecording{
+		Timestamp: startTime,
+		ServiceId: requester.serviceId,
+		Endpoint:  endpoint,
+		Method:    method,
+		Params:    paramsBytes,
+		Latency:   time.Since(startTime),
+	}
+	if requestErr != nil {
+		recordError(&recording, requestErr)
+	} else {
+		recording.ResponseBody = string(responseBodyBytes)
+	}
+
+	// A failure to record shouldn't fail the request itself
+	if err := requester.recorder.record(recording); err != nil {
+		logrus.Warnf("Could not record request for method '%v' to service %v: %v", method, requester.serviceId, err)
+	}
+	return responseBodyBytes, requestErr
+}
+
+// Records the error a request failed with, along with what's needed to rebuild it with its type on replay
+func recordError(recording *RpcRecording, requestErr error) {
+	recording.Error = requestErr.Error()
+	var callErr *RpcCallError
+	var transportErr *RpcTransportError
+	var statusErr *RpcHttpStatusError
+	var decodeErr *RpcDecodeError
+	switch {
+	case AsRpcError(requestErr, &callErr):
+		recording.ErrorKind = RPC_ERROR_KIND_CALL
+		recording.CallError = &JsonRpcError{
+			Code:    callErr.Code,
+			Message: callErr.Message,
+			Data:    callErr.Data,
+		}
+	case AsRpcError(requestErr, &transportErr):
+		recording.ErrorKind = RPC_ERROR_KIND_TRANSPORT
+		recording.ErrorUrl = transportErr.Url
+		recording.ErrorCause = transportErr.Cause.Error()
+	case AsRpcError(requestErr, &statusErr):
+		recording.ErrorKind = RPC_ERROR_KIND_HTTP_STATUS
+		recording.ErrorUrl = statusErr.Url
+		recording.ErrorStatusCode = statusErr.StatusCode
+		recording.ErrorBody = statusErr.Body
+	case AsRpcError(requestErr, &decodeErr):
+		recording.ErrorKind = RPC_ERROR_KIND_DECODE
+		recording.ErrorBody = decodeErr.Body
+		recording.ErrorCause = decodeErr.Cause.Error()
+	default:
+		recording.ErrorKind = RPC_ERROR_KIND_OTHER
+	}
+}
@@ -0,0 +1,133 @@
+package gecko_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testServiceId = "boot-node-0"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	statusResponse := `{"jsonrpc":"2.0","result":{"status":"Accepted"},"id":1}`
+	buffer := &bytes.Buffer{}
+	recorder := NewRpcRecorder(buffer)
+	recordingClient := clientFromRequester(newMockedJsonRpcRequester(statusResponse)).WithRecorder(recorder, testServiceId)
+
+	status, err := recordingClient.XChainApi().GetTxStatus("txId")
+	assert.NoError(t, err)
+	assert.Equal(t, "Accepted", status)
+
+	recordings, err := LoadRpcRecordings(bytes.NewReader(buffer.Bytes()))
+	assert.NoError(t, err)
+	if !assert.Equal(t, 1, len(recordings)) {
+		return
+	}
+	recording := recordings[0]
+	assert.Equal(t, testServiceId, recording.ServiceId)
+	assert.Equal(t, xchainEndpoint, recording.Endpoint)
+	assert.Equal(t, "avm.getTxStatus", recording.Method)
+	assert.JSONEq(t, `{"txID":"txId"}`, string(recording.Params))
+	assert.Equal(t, statusResponse, recording.ResponseBody)
+	assert.Empty(t, recording.Error)
+
+	replayClient := NewReplayGeckoClient(recordings, testServiceId)
+	replayedStatus, err := replayClient.XChainApi().GetTxStatus("txId")
+	assert.NoError(t, err)
+	assert.Equal(t, "Accepted", replayedStatus)
+
+	// Every recording can only be served once
+	_, err = replayClient.XChainApi().GetTxStatus("txId")
+	assert.Error(t, err)
+}
+
+func TestReplayPreservesCallErrors(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	recorder := NewRpcRecorder(buffer)
+	flakyRequester := newFlakyJsonRpcRequester(
+		`{"jsonrpc":"2.0","result":{"txID":"importTxId"},"id":1}`,
+		&RpcCallError{Method: "avm.importAVA", Code: -32000, Message: "problem issuing transaction: no import inputs"})
+	recordingClient := clientFromRequester(flakyRequester).WithRecorder(recorder, testServiceId)
+	_, err := recordingClient.XChainApi().ImportAVA("X-address", "user", "password")
+	assert.True(t, IsRpcError(err, ErrNoImportInputs))
+	_, err = recordingClient.XChainApi().ImportAVA("X-address", "user", "password")
+	assert.NoError(t, err)
+
+	recordings, err := LoadRpcRecordings(bytes.NewReader(buffer.Bytes()))
+	assert.NoError(t, err)
+	replayClient := NewReplayGeckoClient(recordings, testServiceId)
+	_, err = replayClient.XChainApi().ImportAVA("X-address", "user", "password")
+	assert.True(t, IsRpcError(err, ErrNoImportInputs))
+	txId, err := replayClient.XChainApi().ImportAVA("X-address", "user", "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "importTxId", txId)
+}
+
+func TestReplayPreservesErrorTypes(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	recorder := NewRpcRecorder(buffer)
+	flakyRequester := newFlakyJsonRpcRequester(
+		`{"jsonrpc":"2.0","result":{"status":"Accepted"},"id":1}`,
+		&RpcTransportError{Url: "http://1.2.3.4:9650/ext/bc/X", Cause: errors.New("connection refused")},
+		&RpcHttpStatusError{Url: "http://1.2.3.4:9650/ext/bc/X", StatusCode: http.StatusServiceUnavailable, Body: "unavailable"},
+		&RpcDecodeError{Body: "not json", Cause: errors.New("invalid character")})
+	recordingClient := clientFromRequester(flakyRequester).WithRecorder(recorder, testServiceId)
+	for i := 0; i < 3; i++ {
+		_, err := recordingClient.XChainApi().GetTxStatus("txId")
+		assert.Error(t, err)
+	}
+
+	recordings, err := LoadRpcRecordings(bytes.NewReader(buffer.Bytes()))
+	assert.NoError(t, err)
+	replayClient := NewReplayGeckoClient(recordings, testServiceId)
+
+	_, err = replayClient.XChainApi().GetTxStatus("txId")
+	var transportErr *RpcTransportError
+	if assert.True(t, AsRpcError(err, &transportErr)) {
+		assert.Equal(t, "http://1.2.3.4:9650/ext/bc/X", transportErr.Url)
+	}
+	assert.True(t, IsTransientRpcError(err), "A replayed transport error should be classified as it was when recorded")
+
+	_, err = replayClient.XChainApi().GetTxStatus("txId")
+	var statusErr *RpcHttpStatusError
+	if assert.True(t, AsRpcError(err, &statusErr)) {
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+		assert.Equal(t, "unavailable", statusErr.Body)
+	}
+
+	_, err = replayClient.XChainApi().GetTxStatus("txId")
+	var decodeErr *RpcDecodeError
+	if assert.True(t, AsRpcError(err, &decodeErr)) {
+		assert.Equal(t, "not json", decodeErr.Body)
+	}
+	assert.False(t, IsTransientRpcError(err))
+}
+
+func TestReplayFiltersByService(t *testing.T) {
+	recordings := []RpcRecording{
+		{
+			ServiceId:    "other-node",
+			Endpoint:     infoEndpoint,
+			Method:       "info.getNodeID",
+			Params:       json.RawMessage(`{}`),
+			ResponseBody: `{"jsonrpc":"2.0","result":{"nodeID":"otherNodeId"},"id":1}`,
+		},
+		{
+			ServiceId:    testServiceId,
+			Endpoint:     infoEndpoint,
+			Method:       "info.getNodeID",
+			Params:       json.RawMessage(`{}`),
+			ResponseBody: `{"jsonrpc":"2.0","result":{"nodeID":"testNodeId"},"id":1}`,
+		},
+	}
+
+	nodeId, err := NewReplayGeckoClient(recordings, testServiceId).InfoApi().GetNodeId()
+	assert.NoError(t, err)
+	assert.Equal(t, "testNodeId", nodeId)
+}
@@ -0,0 +1,129 @@
+package gecko_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+Serves recorded responses back to the Gecko client, so that a sequence of requests recorded during a test can be
+reproduced offline.
+
+Responses for each method are served in the order they were recorded. Params aren't used for matching, because
+workflows put values like the current time in their params and so never make byte-identical requests twice; instead, a
+mismatch against the recorded params is logged as a warning, because it likely means the replay has diverged.
+*/
+type replayJsonRpcRequester struct {
+	mutex *sync.Mutex
+
+	// "endpoint method" -> recordings for that endpoint & method not yet served, in the order they were made
+	unservedRecordings map[string][]RpcRecording
+}
+
+func getReplayKey(endpoint string, method string) string {
+	return fmt.Sprintf("%v %v", endpoint, method)
+}
+
+func newReplayJsonRpcRequester(recordings []RpcRecording) *replayJsonRpcRequester {
+	unservedRecordings := make(map[string][]RpcRecording)
+	for _, recording := range recordings {
+		key := getReplayKey(recording.Endpoint, recording.Method)
+		unservedRecordings[key] = append(unservedRecordings[key], recording)
+	}
+	return &replayJsonRpcRequester{
+		mutex:              &sync.Mutex{},
+		unservedRecordings: unservedRecordings,
+	}
+}
+
+func (requester *replayJsonRpcRequester) makeRpcRequest(ctx context.Context, endpoint string, method string, params map[string]interface{}) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "Context was done before replaying request for method '%v'", method)
+	}
+
+	key := getReplayKey(endpoint, method)
+	requester.mutex.Lock()
+	remaining := requester.unservedRecordings[key]
+	if len(remaining) == 0 {
+		requester.mutex.Unlock()
+		return nil, stacktrace.NewError("No more recorded responses for method '%v' on endpoint '%v'", method, endpoint)
+	}
+	recording := remaining[0]
+	requester.unservedRecordings[key] = remaining[1:]
+	requester.mutex.Unlock()
+
+	paramsBytes, err := json.Marshal(params)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Could not serialize params '%v' for comparison with recording", params)
+	}
+	if !bytes.Equal(paramsBytes, recording.Params) {
+		logrus.Warnf(
+			"Replayed request for method '%v' has params %v, but the recorded request had params %v",
+			method,
+			string(paramsBytes),
+			string(recording.Params))
+	}
+
+	if recording.CallError != nil {
+		return nil, stacktrace.Propagate(
+			&RpcCallError{
+				Method:  method,
+				Code:    recording.CallError.Code,
+				Message: recording.CallError.Message,
+				Data:    recording.CallError.Data,
+			},
+			"Replayed RPC call failed")
+	}
+	if recording.Error != "" {
+		return nil, stacktrace.Propagate(getReplayedError(recording), "Replayed request failed")
+	}
+	return []byte(recording.ResponseBody), nil
+}
+
+// Rebuilds the error a recorded request failed with, with the same type so that it's classified the same as when recorded
+func getReplayedError(recording RpcRecording) error {
+	switch recording.ErrorKind {
+	case RPC_ERROR_KIND_TRANSPORT:
+		return &RpcTransportError{
+			Url:   recording.ErrorUrl,
+			Cause: errors.New(recording.ErrorCause),
+		}
+	case RPC_ERROR_KIND_HTTP_STATUS:
+		return &RpcHttpStatusError{
+			Url:        recording.ErrorUrl,
+			StatusCode: recording.ErrorStatusCode,
+			Body:       recording.ErrorBody,
+		}
+	case RPC_ERROR_KIND_DECODE:
+		return &RpcDecodeError{
+			Body:  recording.ErrorBody,
+			Cause: errors.New(recording.ErrorCause),
+		}
+	default:
+		return errors.New(recording.Error)
+	}
+}
+
+/*
+Creates a client which serves the given recordings rather than talking to a real Gecko node
+
+Args:
+	recordings: The recordings to serve
+	serviceId: If non-empty, only recordings of requests made to this service will be served
+*/
+func NewReplayGeckoClient(recordings []RpcRecording, serviceId string) *GeckoClient {
+	filteredRecordings := []RpcRecording{}
+	for _, recording := range recordings {
+		if serviceId == "" || recording.ServiceId == serviceId {
+			filteredRecordings = append(filteredRecordings, recording)
+		}
+	}
+	return clientFromRequester(newReplayJsonRpcRequester(filteredRecordings))
+}
@@ -1,16 +1,21 @@
 package gecko_client
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/palantir/stacktrace"
 )
 
 const (
 	xchainEndpoint = "ext/bc/X"
+
+	// Endpoint of the AVM's static API, which doesn't depend on any particular chain
+	avmStaticEndpoint = "ext/vm/avm"
 )
 
 type XChainApi struct {
 	rpcRequester jsonRpcRequester
+	ctx          context.Context
 }
 
 func (api XChainApi) ImportKey(username string, password string, privateKey string) (string, error) {
@@ -19,7 +24,7 @@ func (api XChainApi) ImportKey(username string, password string, privateKey stri
 		"password": password,
 		"privateKey": privateKey,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.importKey", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.importKey", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -38,7 +43,7 @@ func (api XChainApi) ExportAVA(to string, amount int64, username string, passwor
 		"username": username,
 		"password": password,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.exportAVA", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.exportAVA", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -56,7 +61,7 @@ func (api XChainApi) ImportAVA(to string, username string, password string) (str
 		"username": username,
 		"password": password,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.importAVA", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.importAVA", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -72,7 +77,7 @@ func (api XChainApi) GetTxStatus(txnId string) (string, error) {
 	params := map[string]interface{}{
 		"txID": txnId,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.getTxStatus", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.getTxStatus", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -89,7 +94,7 @@ func (api XChainApi) GetBalance(address string, assetId string) (*AccountWithUtx
 		"address": address,
 		"assetID": assetId,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.getBalance", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.getBalance", params)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "Error making request")
 	}
@@ -109,7 +114,7 @@ func (api XChainApi) Send(amount int64, assetId string, to string, username stri
 		"username": username,
 		"password": password,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.send", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.send", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -126,7 +131,7 @@ func (api XChainApi) CreateAddress(username string, password string) (string, er
 		"username": username,
 		"password": password,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.createAddress", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.createAddress", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -142,7 +147,7 @@ func (api XChainApi) IssueTx(tx string) (string, error) {
 	params := map[string]interface{}{
 		"tx": tx,
 	}
-	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(xchainEndpoint, "avm.issueTx", params)
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.issueTx", params)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Error making request")
 	}
@@ -153,3 +158,215 @@ func (api XChainApi) IssueTx(tx string) (string, error) {
 	}
 	return response.Result.TxID, nil
 }
+
+// ============= Assets ====================
+
+// Creates a new fixed-cap asset whose entire supply is given to the initial holders, returning the asset ID
+func (api XChainApi) CreateFixedCapAsset(
+		username string,
+		password string,
+		name string,
+		symbol string,
+		denomination int,
+		initialHolders []InitialHolder) (string, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+		"name": name,
+		"symbol": symbol,
+		"denomination": denomination,
+		"initialHolders": initialHolders,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.createFixedCapAsset", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response CreateAssetResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.AssetID, nil
+}
+
+// Creates a new variable-cap asset which can be minted by the given minter sets, returning the asset ID
+func (api XChainApi) CreateVariableCapAsset(
+		username string,
+		password string,
+		name string,
+		symbol string,
+		denomination int,
+		minterSets []MinterSet) (string, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+		"name": name,
+		"symbol": symbol,
+		"denomination": denomination,
+		"minterSets": minterSets,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.createVariableCapAsset", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response CreateAssetResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.AssetID, nil
+}
+
+// Mints more of a variable-cap asset controlled by the user, returning the transaction ID
+func (api XChainApi) Mint(amount int64, assetId string, to string, username string, password string) (string, error) {
+	params := map[string]interface{}{
+		"amount": amount,
+		"assetID": assetId,
+		"to": to,
+		"username": username,
+		"password": password,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.mint", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response MintResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.TxID, nil
+}
+
+// Gets the name, symbol, and denomination of the asset with the given ID or alias
+func (api XChainApi) GetAssetDescription(assetId string) (*AssetDescription, error) {
+	params := map[string]interface{}{
+		"assetID": assetId,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.getAssetDescription", params)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetAssetDescriptionResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return &response.Result, nil
+}
+
+// ============= Balances & UTXOs ====================
+
+// Gets the balance of every asset held by the given address
+func (api XChainApi) GetAllBalances(address string) ([]AssetBalance, error) {
+	params := map[string]interface{}{
+		"address": address,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.getAllBalances", params)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetAllBalancesResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Balances, nil
+}
+
+// Gets the UTXOs which reference any of the given addresses
+func (api XChainApi) GetUTXOs(addresses []string) ([]string, error) {
+	params := map[string]interface{}{
+		"addresses": addresses,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.getUTXOs", params)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetUTXOsResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Utxos, nil
+}
+
+// ============= Keys & Addresses ====================
+
+// Lists the X-Chain addresses controlled by the user
+func (api XChainApi) ListAddresses(username string, password string) ([]string, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.listAddresses", params)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response ListAddressesResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return nil, stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Addresses, nil
+}
+
+// Returns the private key controlling the given address, which must be controlled by the user
+func (api XChainApi) ExportKey(username string, password string, address string) (string, error) {
+	params := map[string]interface{}{
+		"username": username,
+		"password": password,
+		"address": address,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.exportKey", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response ExportKeyResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.PrivateKey, nil
+}
+
+// ============= Transactions & Genesis ====================
+
+// Gets the serialized bytes of the transaction with the given ID
+func (api XChainApi) GetTx(txnId string) (string, error) {
+	params := map[string]interface{}{
+		"txID": txnId,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, xchainEndpoint, "avm.getTx", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response GetTxResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Tx, nil
+}
+
+/*
+Serializes the given asset definitions into the genesis bytes of a new AVM chain, using the AVM's static API
+
+Args:
+	genesisData: Mapping of asset alias -> definition of the asset
+*/
+func (api XChainApi) BuildGenesis(genesisData map[string]GenesisAssetDefinition) (string, error) {
+	params := map[string]interface{}{
+		"genesisData": genesisData,
+	}
+	responseBodyBytes, err := api.rpcRequester.makeRpcRequest(api.ctx, avmStaticEndpoint, "avm.buildGenesis", params)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error making request")
+	}
+
+	var response BuildGenesisResponse
+	if err := json.Unmarshal(responseBodyBytes, &response); err != nil {
+		return "", stacktrace.Propagate(err, "Error unmarshalling JSON response")
+	}
+	return response.Result.Bytes, nil
+}
@@ -136,3 +136,196 @@ func TestXChainIssueTx(t *testing.T) {
 }
 
 
+
+func TestXChainCreateFixedCapAsset(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "assetID":"ZiKfqRXCZgHLgZ4rxGU9Qbycdzuq5DRY4tdSNS9ku8kcNxNLD"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	initialHolders := []InitialHolder{
+		{Amount: 10000, Address: "X-7u5FQArVaMSgGZzeTE9ckheWtDhU5T3KS"},
+	}
+	assetId, err := client.XChainApi().CreateFixedCapAsset("myUsername", "myPassword", "myFixedCapAsset", "MFCA", 0, initialHolders)
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "ZiKfqRXCZgHLgZ4rxGU9Qbycdzuq5DRY4tdSNS9ku8kcNxNLD", assetId)
+}
+
+func TestXChainCreateVariableCapAsset(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "assetID":"2QbZFE7J4MAny9iXHUwq8Pz8SpFhWk3maCw4SkinVPv6wPmAbK"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	minterSets := []MinterSet{
+		{Minters: []string{"X-4peJsFvhdn7XjhNF4HWAQy6YaJts27s9q"}, Threshold: 1},
+	}
+	assetId, err := client.XChainApi().CreateVariableCapAsset("myUsername", "myPassword", "myVariableCapAsset", "MVCA", 0, minterSets)
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "2QbZFE7J4MAny9iXHUwq8Pz8SpFhWk3maCw4SkinVPv6wPmAbK", assetId)
+}
+
+func TestXChainMint(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "txID":"2oGdPdfw2qcNUHeqjw8sU2hPVrFyNUTgn6A8HenDra7oLCDtja"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	transactionId, err := client.XChainApi().Mint(10000000, "i1EqsthjiFTxunrj8WD2xFSrQ5p2siEKQacmCCB5qBFVqfSL2", "X-wmYTMFJhZj6GbLumnJmb8cm9cbR5AEzh4", "myUsername", "myPassword")
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "2oGdPdfw2qcNUHeqjw8sU2hPVrFyNUTgn6A8HenDra7oLCDtja", transactionId)
+}
+
+func TestXChainGetAssetDescription(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "assetID":"21d7KVtPrubc5fHr6CGNcgbUb4seUjmZKr35ZX7BZb5iP8pXWA",
+        "name":"AVA",
+        "symbol":"AVA",
+        "denomination":"9"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	description, err := client.XChainApi().GetAssetDescription("AVA")
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "21d7KVtPrubc5fHr6CGNcgbUb4seUjmZKr35ZX7BZb5iP8pXWA", description.AssetID)
+	assert.Equal(t, "AVA", description.Name)
+	assert.Equal(t, "AVA", description.Symbol)
+	assert.Equal(t, "9", description.Denomination)
+}
+
+func TestXChainGetAllBalances(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "balances":[
+            {
+                "asset":"AVA",
+                "balance":"102"
+            },
+            {
+                "asset":"2sdnziCz37Jov3QSNMXcFRGFJ1tgauaj6L7qfk7yUcRPfQMC79",
+                "balance":"10000"
+            }
+        ]
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	balances, err := client.XChainApi().GetAllBalances("X-Bg6e45gxCUTLXcfUuoy3go2U6V3bRZ5jH")
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, 2, len(balances))
+	assert.Equal(t, "AVA", balances[0].Asset)
+	assert.Equal(t, "102", balances[0].Balance)
+	assert.Equal(t, "2sdnziCz37Jov3QSNMXcFRGFJ1tgauaj6L7qfk7yUcRPfQMC79", balances[1].Asset)
+	assert.Equal(t, "10000", balances[1].Balance)
+}
+
+func TestXChainGetUTXOs(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "utxos":[
+            "4Z7RkSvvW1e1fAbBGzsVcLAkYM3zPxBqjfRSLjLHwDU5mLNu5UbtZcF1EtZGwzJLqc9kUnPBqhQNTv7WkA1wGPNcYDAkhG2WM6mzSj6VaLk3bk6hDQEg8bKeUAwQxPHpTb72RLkT1CuDdcQFpLKDZfHoWXhoXZBeRUpERDJqBNZeCGs7U8UwVXLeeFzFxWWgGqyPhGBuqGD8SaYw"
+        ]
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	utxos, err := client.XChainApi().GetUTXOs([]string{"X-EddAWvKrLtRXUuTBmADmM3MiAyE8pZ8wg"})
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, 1, len(utxos))
+	assert.Equal(t, "4Z7RkSvvW1e1fAbBGzsVcLAkYM3zPxBqjfRSLjLHwDU5mLNu5UbtZcF1EtZGwzJLqc9kUnPBqhQNTv7WkA1wGPNcYDAkhG2WM6mzSj6VaLk3bk6hDQEg8bKeUAwQxPHpTb72RLkT1CuDdcQFpLKDZfHoWXhoXZBeRUpERDJqBNZeCGs7U8UwVXLeeFzFxWWgGqyPhGBuqGD8SaYw", utxos[0])
+}
+
+func TestXChainListAddresses(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "addresses":[
+            "X-B5ScDsqrAE3VvnTFPpVGHUFYGrcSc7rPh",
+            "X-CzCFtHPC6TTJ6ni8TncqCgdMBRJDhHWXY"
+        ]
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	addresses, err := client.XChainApi().ListAddresses("myUsername", "myPassword")
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, []string{"X-B5ScDsqrAE3VvnTFPpVGHUFYGrcSc7rPh", "X-CzCFtHPC6TTJ6ni8TncqCgdMBRJDhHWXY"}, addresses)
+}
+
+func TestXChainExportKey(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "privateKey":"2w4XiXxPfQK4TypYqnohRL8DRNTz9cGiGmwQ1zmgEqD9c9KWLq"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	privateKey, err := client.XChainApi().ExportKey("myUsername", "myPassword", "X-7u5FQArVaMSgGZzeTE9ckheWtDhU5T3KS")
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "2w4XiXxPfQK4TypYqnohRL8DRNTz9cGiGmwQ1zmgEqD9c9KWLq", privateKey)
+}
+
+func TestXChainGetTx(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "tx":"1111111111111111111111111111111111111111111111111111111111111111111117xnGg8KPD8hBXj8SGgq3fMPfVJJuKDCHNrL1XqR9ADbSsF6VW"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	tx, err := client.XChainApi().GetTx("2QouvMUbQ6oy7yQ9tLvL3L8tGQG2QK1wJ1q1wxw9ZaCmUuNHAq")
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "1111111111111111111111111111111111111111111111111111111111111111111117xnGg8KPD8hBXj8SGgq3fMPfVJJuKDCHNrL1XqR9ADbSsF6VW", tx)
+}
+
+func TestXChainBuildGenesis(t *testing.T) {
+	resultStr := `{
+    "jsonrpc":"2.0",
+    "id"     :1,
+    "result" :{
+        "bytes":"111TNWzUtHKoSvxohjyfEwE2X228ZDGBngZ4mdMUVMnVnjtnawW1b1zbAhzyAM1v6d7ECNj6DXsT7qDmhSEf3DWgXRj7"
+    }
+}`
+	client := clientFromRequester(mockedJsonRpcRequester{resultStr: resultStr})
+	genesisData := map[string]GenesisAssetDefinition{
+		"asset1": {
+			Name:         "myFixedCapAsset",
+			Symbol:       "MFCA",
+			Denomination: 0,
+			InitialState: map[string][]interface{}{
+				"fixedCap": {
+					map[string]interface{}{"amount": 100000, "address": "A9bTQjfYGBFK3JPRJqF2eh3JYL7cHocvy"},
+				},
+			},
+		},
+	}
+	genesisBytes, err := client.XChainApi().BuildGenesis(genesisData)
+	assert.Nil(t, err, "Error message should be nil")
+
+	assert.Equal(t, "111TNWzUtHKoSvxohjyfEwE2X228ZDGBngZ4mdMUVMnVnjtnawW1b1zbAhzyAM1v6d7ECNj6DXsT7qDmhSEf3DWgXRj7", genesisBytes)
+}
@@ -58,4 +58,112 @@ type CreateAddressResponse struct {
 	JsonRpcVersion string                  `json:"jsonrpc"`
 	Result         AddressInfo `json:"result"`
 	Id             int                     `json:"id"`
-}
\ No newline at end of file
+}
+
+// ============= Assets ====================
+type InitialHolder struct {
+	Amount  int64  `json:"amount"`
+	Address string `json:"address"`
+}
+
+type MinterSet struct {
+	Minters   []string `json:"minters"`
+	Threshold int      `json:"threshold"`
+}
+
+type AssetIDInfo struct {
+	AssetID string `json:"assetID"`
+}
+
+type CreateAssetResponse struct {
+	JsonRpcVersion string      `json:"jsonrpc"`
+	Result         AssetIDInfo `json:"result"`
+	Id             int         `json:"id"`
+}
+
+type MintResponse struct {
+	JsonRpcVersion string        `json:"jsonrpc"`
+	Result         XChainTxnInfo `json:"result"`
+	Id             int           `json:"id"`
+}
+
+type AssetDescription struct {
+	AssetID      string `json:"assetID"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	Denomination string `json:"denomination"`
+}
+
+type GetAssetDescriptionResponse struct {
+	JsonRpcVersion string           `json:"jsonrpc"`
+	Result         AssetDescription `json:"result"`
+	Id             int              `json:"id"`
+}
+
+// ============= Balances & UTXOs ====================
+type AssetBalance struct {
+	Asset   string `json:"asset"`
+	Balance string `json:"balance"`
+}
+
+type AssetBalanceList struct {
+	Balances []AssetBalance `json:"balances"`
+}
+
+type GetAllBalancesResponse struct {
+	JsonRpcVersion string           `json:"jsonrpc"`
+	Result         AssetBalanceList `json:"result"`
+	Id             int              `json:"id"`
+}
+
+type UtxoList struct {
+	Utxos []string `json:"utxos"`
+}
+
+type GetUTXOsResponse struct {
+	JsonRpcVersion string   `json:"jsonrpc"`
+	Result         UtxoList `json:"result"`
+	Id             int      `json:"id"`
+}
+
+// ============= Keys & Addresses ====================
+type AddressList struct {
+	Addresses []string `json:"addresses"`
+}
+
+type ListAddressesResponse struct {
+	JsonRpcVersion string      `json:"jsonrpc"`
+	Result         AddressList `json:"result"`
+	Id             int         `json:"id"`
+}
+
+// ============= Transactions & Genesis ====================
+type SerializedTxInfo struct {
+	Tx string `json:"tx"`
+}
+
+type GetTxResponse struct {
+	JsonRpcVersion string           `json:"jsonrpc"`
+	Result         SerializedTxInfo `json:"result"`
+	Id             int              `json:"id"`
+}
+
+type GenesisAssetDefinition struct {
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	Denomination int    `json:"denomination"`
+
+	// Mapping of feature extension (e.g. "fixedCap", "variableCap") -> initial outputs of that extension
+	InitialState map[string][]interface{} `json:"initialState"`
+	Memo         string                   `json:"memo"`
+}
+
+type GenesisBytes struct {
+	Bytes string `json:"bytes"`
+}
+
+type BuildGenesisResponse struct {
+	JsonRpcVersion string       `json:"jsonrpc"`
+	Result         GenesisBytes `json:"result"`
+	Id             int          `json:"id"`
+}
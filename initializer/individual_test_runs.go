@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/test_reports"
+	"github.com/kurtosis-tech/kurtosis/initializer"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// Prefix of the Docker volumes that controllers write their results to, one per test run
+	resultVolumeNamePrefix = "ava-e2e-test-result"
+)
+
+/*
+Runs each of the given tests with its own call to a test suite runner, so that each test's outcome and duration are
+known; the runner itself only reports whether all the tests it ran passed. Each test's controller writes whether it
+failed during network setup or execution, and how long each took, to a result volume made for the test.
+
+NOTE: The tests are run one at a time, because the runner gives each call's tests the same Docker subnets
+
+Args:
+	createTestSuiteRunner: Creates a runner whose controllers will write their results to the given volume
+	testNames: The names of the tests to run, which will be run in sorted order
+*/
+func runTestsIndividually(
+	createTestSuiteRunner func(resultVolumeName string) *initializer.TestSuiteRunner,
+	testNames map[string]bool) []test_reports.TestResult {
+	sortedTestNames := make([]string, 0, len(testNames))
+	for name := range testNames {
+		sortedTestNames = append(sortedTestNames, name)
+	}
+	sort.Strings(sortedTestNames)
+
+	results := make([]test_reports.TestResult, 0, len(sortedTestNames))
+	for _, name := range sortedTestNames {
+		results = append(results, runTestIndividually(createTestSuiteRunner, name))
+	}
+	return results
+}
+
+func runTestIndividually(
+	createTestSuiteRunner func(resultVolumeName string) *initializer.TestSuiteRunner,
+	testName string) test_reports.TestResult {
+	startTime := time.Now()
+	result := test_reports.TestResult{
+		Name:      testName,
+		StartTime: startTime,
+	}
+
+	resultVolumeName := fmt.Sprintf("%v-%v-%v", resultVolumeNamePrefix, testName, startTime.UnixNano())
+	resultVolume := test_reports.NewResultVolume(resultVolumeName)
+	if err := resultVolume.Create(); err != nil {
+		logrus.Errorf("An error occurred creating the result volume for test %v:", testName)
+		logrus.Error(err)
+		result.Status = test_reports.TEST_STATUS_ERRORED
+		result.Error = err.Error()
+		result.DurationSeconds = time.Since(startTime).Seconds()
+		return result
+	}
+	defer func() {
+		if err := resultVolume.Remove(); err != nil {
+			logrus.Warnf("Could not remove the result volume of test %v: %v", testName, err)
+		}
+	}()
+
+	passed, err := createTestSuiteRunner(resultVolumeName).RunTests(map[string]bool{testName: true}, 1)
+	result.DurationSeconds = time.Since(startTime).Seconds()
+	if err != nil {
+		logrus.Errorf("An error occurred running test %v:", testName)
+		logrus.Error(err)
+		result.Status = test_reports.TEST_STATUS_ERRORED
+		result.Error = err.Error()
+	} else if passed {
+		result.Status = test_reports.TEST_STATUS_PASSED
+	} else {
+		result.Status = test_reports.TEST_STATUS_FAILED
+	}
+
+	// A controller that never ran, or crashed, won't have written a result, in which case the report just lacks it
+	controllerResult, err := resultVolume.ReadControllerResult()
+	if err != nil {
+		logrus.Warnf("No setup and execution details will be reported for test %v: %v", testName, err)
+	} else {
+		controllerResult.ApplyTo(&result)
+	}
+	logrus.Infof(
+		"Test %v %v after %.1fs (%.1fs setting up its network, %.1fs executing)",
+		testName,
+		result.Status,
+		result.DurationSeconds,
+		result.NetworkSetupDurationSeconds,
+		result.ExecutionDurationSeconds)
+	return result
+}
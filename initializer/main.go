@@ -1,22 +1,33 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"math"
+	"math/rand"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_networks"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/ava_testsuite"
 	"github.com/kurtosis-tech/ava-e2e-tests/commons/logging"
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/test_reports"
 	"github.com/kurtosis-tech/kurtosis/initializer"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	testNameArgSeparator     = ","
+	listArgSeparator         = ","
 	geckoImageNameEnvVar     = "GECKO_IMAGE_NAME"
 	byzantineImageNameEnvVar = "BYZANTINE_IMAGE_NAME"
+	upgradeImageNameEnvVar   = "UPGRADE_IMAGE_NAME"
+	networkSpecsEnvVar       = "NETWORK_SPECS"
+	certSeedEnvVar           = "CERT_SEED"
+	testResultVolumeEnvVar   = "TEST_RESULT_VOLUME"
 	defaultParallelism       = 4
 
 	// The number of bits to make each test network, which dictates the max number of services a test can spin up
@@ -50,6 +61,18 @@ func main() {
 		"The name of a pre-built Byzantine Gecko image, on the local Docker engine",
 	)
 
+	upgradeImageNameArg := flag.String(
+		"upgrade-image-name",
+		"",
+		"The name of a pre-built Gecko image to upgrade nodes running the Gecko image to in the rolling upgrade test (default or empty: skip the test)",
+	)
+
+	networkSpecsDirpathArg := flag.String(
+		"network-specs-dirpath",
+		"",
+		"Directory of JSON network spec files, one per test and named after it (e.g. stakingNetworkRpcWorkflowTest.json), giving the topology of each test's network; run.sh passes the repo's network_specs directory",
+	)
+
 	testControllerImageNameArg := flag.String(
 		"test-controller-image-name",
 		"",
@@ -62,6 +85,18 @@ func main() {
 		"Comma-separated list of test names to run (default or empty: run all tests)",
 	)
 
+	includeTagsArg := flag.String(
+		"include-tags",
+		"",
+		fmt.Sprintf("Comma-separated list of tags, only tests with at least one of which will be run (default or empty: don't filter by tag). Tags: %v", strings.Join(ava_testsuite.GetAllTags(), ", ")),
+	)
+
+	excludeTagsArg := flag.String(
+		"exclude-tags",
+		"",
+		"Comma-separated list of tags, tests with any of which won't be run, even if named in --test-names",
+	)
+
 	initializerLogLevelArg := flag.String(
 		"initializer-log-level",
 		"debug",
@@ -80,23 +115,71 @@ func main() {
 		"Number of tests to run in parallel",
 	)
 
+	certSeedArg := flag.Int64(
+		"cert-seed",
+		0,
+		"Seed to generate the test nodes' certs (and so their node IDs) from, to reproduce an earlier run (default or 0: pick a random seed)",
+	)
+
+	jsonReportFilepathArg := flag.String(
+		"json-report-filepath",
+		"",
+		"File to write a JSON report of the run's per-test results to (default or empty: don't write one). Writing a report runs the tests one at a time, ignoring --parallelism.",
+	)
+
+	junitReportFilepathArg := flag.String(
+		"junit-report-filepath",
+		"",
+		"File to write a JUnit XML report of the run's per-test results to (default or empty: don't write one). Writing a report runs the tests one at a time, ignoring --parallelism.",
+	)
+
+	repeatArg := flag.Uint(
+		"repeat",
+		1,
+		"Number of times to run the selected tests, to measure how flaky they are. Repeated tests run one at a time, ignoring --parallelism. Each iteration gets its own cert seed unless --cert-seed is given.",
+	)
+
+	untilFailureArg := flag.Bool(
+		"until-failure",
+		false,
+		"With --repeat, stop after the first iteration in which a test doesn't pass. As with --repeat, tests run one at a time, ignoring --parallelism.",
+	)
+
+	flakinessReportFilepathArg := flag.String(
+		"flakiness-report-filepath",
+		"",
+		"With --repeat, file to write a JSON report of each test's pass rate and failed iterations to (default or empty: don't write one)",
+	)
+
 	flag.Parse()
 
 	logrus.Info("Welcome to the Ava E2E test suite, powered by the Kurtosis framework")
+	if *networkSpecsDirpathArg == "" {
+		logrus.Fatalf("A network specs directory must be given with --network-specs-dirpath")
+		os.Exit(1)
+	}
+	networkSpecs, err := ava_networks.LoadNetworkSpecsDirectory(*networkSpecsDirpathArg)
+	if err != nil {
+		logrus.Error("An error occurred loading the network specs:")
+		logrus.Error(err)
+		os.Exit(1)
+	}
+	// The controllers get the specs through an env var, as they can't read the initializer's filesystem
+	networkSpecsJson, err := json.Marshal(networkSpecs)
+	if err != nil {
+		logrus.Error("An error occurred serializing the network specs to pass to the controllers:")
+		logrus.Error(err)
+		os.Exit(1)
+	}
+
 	testSuite := ava_testsuite.AvaTestSuite{
 		ByzantineImageName: *byzantineImageNameArg,
 		NormalImageName:    *geckoImageNameArg,
+		UpgradeImageName:   *upgradeImageNameArg,
+		NetworkSpecs:       networkSpecs,
 	}
 	if *doListArg {
-		testNames := []string{}
-		for name, _ := range testSuite.GetTests() {
-			testNames = append(testNames, name)
-		}
-		sort.Strings(testNames)
-
-		for _, name := range testNames {
-			fmt.Println("- " + name)
-		}
+		printTestCatalog(testSuite)
 		os.Exit(0)
 	}
 
@@ -118,27 +201,138 @@ func main() {
 		os.Exit(1)
 	}
 
-	testNamesArgStr := strings.TrimSpace(*testNamesArg)
-	testNames := map[string]bool{}
-	if len(testNamesArgStr) > 0 {
-		testNamesList := strings.Split(testNamesArgStr, testNameArgSeparator)
-		for _, name := range testNamesList {
-			testNames[name] = true
+	if *repeatArg == 0 {
+		logrus.Fatalf("The number of times to repeat the tests must be positive")
+		os.Exit(1)
+	}
+	isRepeating := *repeatArg > 1
+	if !isRepeating && (*untilFailureArg || *flakinessReportFilepathArg != "") {
+		logrus.Fatalf("--until-failure and --flakiness-report-filepath can only be used with --repeat")
+		os.Exit(1)
+	}
+	if isRepeating && (*jsonReportFilepathArg != "" || *junitReportFilepathArg != "") {
+		logrus.Fatalf("--repeat can't be used with the JSON or JUnit report flags; use --flakiness-report-filepath instead")
+		os.Exit(1)
+	}
+
+	// Zero means "unseeded" to the controller, so the random seed must never be zero
+	seedRandom := rand.New(rand.NewSource(time.Now().UnixNano()))
+	getCertSeed := func() int64 {
+		if *certSeedArg != 0 {
+			return *certSeedArg
+		}
+		return seedRandom.Int63n(math.MaxInt64) + 1
+	}
+
+	testNames, skippedTests, err := testSuite.SelectTests(
+		splitListArg(*testNamesArg),
+		splitListArg(*includeTagsArg),
+		splitListArg(*excludeTagsArg))
+	if err != nil {
+		logrus.Error("An error occurred selecting the tests to run:")
+		logrus.Error(err)
+		os.Exit(1)
+	}
+	for name, reason := range skippedTests {
+		logrus.Warnf("Skipping test %v because %v", name, reason)
+	}
+	if len(testNames) == 0 {
+		logrus.Error("No tests match the given test names and tags")
+		os.Exit(1)
+	}
+
+	// The result volume is only used when running tests individually; the controller doesn't write a result without one
+	createTestSuiteRunner := func(certSeed int64, resultVolumeName string) *initializer.TestSuiteRunner {
+		return initializer.NewTestSuiteRunner(
+			testSuite,
+			*testControllerImageNameArg,
+			*controllerLogLevelArg,
+			map[string]string{
+				geckoImageNameEnvVar:     *geckoImageNameArg,
+				byzantineImageNameEnvVar: *byzantineImageNameArg,
+				upgradeImageNameEnvVar:   *upgradeImageNameArg,
+				networkSpecsEnvVar:       string(networkSpecsJson),
+				certSeedEnvVar:           strconv.FormatInt(certSeed, 10),
+				testResultVolumeEnvVar:   resultVolumeName,
+			},
+			networkWidthBits)
+	}
+
+	if isRepeating {
+		logrus.Warnf(
+			"Repeating the tests runs them one at a time, ignoring the parallelism of %v, so each iteration will take much longer than a normal run",
+			*parallelismArg)
+
+		startTime := time.Now()
+		report := runTestsRepeatedly(createTestSuiteRunner, getCertSeed, testNames, int(*repeatArg), *untilFailureArg)
+		report.StartTime = startTime
+		report.DurationSeconds = time.Since(startTime).Seconds()
+		report.GeckoImageName = *geckoImageNameArg
+		report.ByzantineImageName = *byzantineImageNameArg
+		report.UpgradeImageName = *upgradeImageNameArg
+		logFlakinessReport(report)
+
+		if *flakinessReportFilepathArg != "" {
+			if err := report.WriteJson(*flakinessReportFilepathArg); err != nil {
+				logrus.Error(err)
+				os.Exit(1)
+			}
+			logrus.Infof("Wrote flakiness report to %v", *flakinessReportFilepathArg)
+		}
+
+		if report.AllRunsPassed() {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
 		}
 	}
 
-	testSuiteRunner := initializer.NewTestSuiteRunner(
-		testSuite,
-		*testControllerImageNameArg,
-		*controllerLogLevelArg,
-		map[string]string{
-			geckoImageNameEnvVar:     *geckoImageNameArg,
-			byzantineImageNameEnvVar: *byzantineImageNameArg,
-		},
-		networkWidthBits)
+	certSeed := getCertSeed()
+	logrus.Infof("Using cert seed %v; to reproduce this run's node IDs, rerun with --cert-seed=%v", certSeed, certSeed)
+
+	if *jsonReportFilepathArg != "" || *junitReportFilepathArg != "" {
+		logrus.Warnf(
+			"Writing a report runs the tests one at a time, ignoring the parallelism of %v, so the run will take much longer than without one",
+			*parallelismArg)
+
+		startTime := time.Now()
+		report := test_reports.TestRunReport{
+			StartTime:          startTime,
+			GeckoImageName:     *geckoImageNameArg,
+			ByzantineImageName: *byzantineImageNameArg,
+			UpgradeImageName:   *upgradeImageNameArg,
+			CertSeed:           certSeed,
+		}
+		createReportTestSuiteRunner := func(resultVolumeName string) *initializer.TestSuiteRunner {
+			return createTestSuiteRunner(certSeed, resultVolumeName)
+		}
+		report.Results = runTestsIndividually(createReportTestSuiteRunner, testNames)
+		report.DurationSeconds = time.Since(startTime).Seconds()
+
+		if *jsonReportFilepathArg != "" {
+			if err := report.WriteJson(*jsonReportFilepathArg); err != nil {
+				logrus.Error(err)
+				os.Exit(1)
+			}
+			logrus.Infof("Wrote JSON test report to %v", *jsonReportFilepathArg)
+		}
+		if *junitReportFilepathArg != "" {
+			if err := report.WriteJUnit(*junitReportFilepathArg); err != nil {
+				logrus.Error(err)
+				os.Exit(1)
+			}
+			logrus.Infof("Wrote JUnit test report to %v", *junitReportFilepathArg)
+		}
+
+		if report.AllTestsPassed() {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+	}
 
 	// Create the container based on the configurations, but don't start it yet.
-	allTestsSucceeded, error := testSuiteRunner.RunTests(testNames, *parallelismArg)
+	allTestsSucceeded, error := createTestSuiteRunner(certSeed, "").RunTests(testNames, *parallelismArg)
 	if error != nil {
 		logrus.Error("An error occurred running the tests:")
 		logrus.Error(error)
@@ -151,3 +345,35 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// Splits a comma-separated list arg into a set, which is empty if the arg is empty
+func splitListArg(arg string) map[string]bool {
+	result := map[string]bool{}
+	trimmedArg := strings.TrimSpace(arg)
+	if len(trimmedArg) == 0 {
+		return result
+	}
+	for _, elem := range strings.Split(trimmedArg, listArgSeparator) {
+		result[strings.TrimSpace(elem)] = true
+	}
+	return result
+}
+
+// Prints every test in the suite with its tags and description, and the reason it can't be run if it can't
+func printTestCatalog(testSuite ava_testsuite.AvaTestSuite) {
+	catalog := testSuite.GetTestCatalog()
+	testNames := []string{}
+	for name := range catalog {
+		testNames = append(testNames, name)
+	}
+	sort.Strings(testNames)
+
+	for _, name := range testNames {
+		entry := catalog[name]
+		fmt.Printf("- %v [%v]\n", name, strings.Join(entry.Metadata.Tags, ", "))
+		fmt.Printf("    %v\n", entry.Metadata.Description)
+		if !entry.IsAvailable() {
+			fmt.Printf("    UNAVAILABLE: %v\n", entry.UnavailableReason)
+		}
+	}
+}
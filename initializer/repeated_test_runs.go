@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/kurtosis-tech/ava-e2e-tests/commons/test_reports"
+	"github.com/kurtosis-tech/kurtosis/initializer"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+Runs the given tests repeatedly to measure how flaky they are, giving each iteration its own cert seed so that a failed
+iteration can be reproduced
+
+Args:
+	createTestSuiteRunner: Creates a runner whose tests will use the given cert seed, and whose controllers will write
+		their results to the given volume
+	getCertSeed: Gets the cert seed for the next iteration
+	testNames: The names of the tests to run in each iteration
+	iterations: The number of iterations to run
+	untilFailure: If true, no more iterations are run after the first one in which a test doesn't pass
+*/
+func runTestsRepeatedly(
+	createTestSuiteRunner func(certSeed int64, resultVolumeName string) *initializer.TestSuiteRunner,
+	getCertSeed func() int64,
+	testNames map[string]bool,
+	iterations int,
+	untilFailure bool) test_reports.FlakinessReport {
+	completedIterations := make([]test_reports.TestRunIteration, 0, iterations)
+	for i := 1; i <= iterations; i++ {
+		certSeed := getCertSeed()
+		logrus.Infof("Starting iteration %v of %v with cert seed %v", i, iterations, certSeed)
+		createIterationTestSuiteRunner := func(resultVolumeName string) *initializer.TestSuiteRunner {
+			return createTestSuiteRunner(certSeed, resultVolumeName)
+		}
+		iteration := test_reports.TestRunIteration{
+			Iteration: i,
+			CertSeed:  certSeed,
+			Results:   runTestsIndividually(createIterationTestSuiteRunner, testNames),
+		}
+		completedIterations = append(completedIterations, iteration)
+
+		if untilFailure && !iteration.AllTestsPassed() {
+			logrus.Infof("Stopping after iteration %v because a test didn't pass", i)
+			break
+		}
+	}
+	return test_reports.NewFlakinessReport(iterations, completedIterations)
+}
+
+// Logs each test's pass rate, along with the seed and timing of every run of it that didn't pass
+func logFlakinessReport(report test_reports.FlakinessReport) {
+	logrus.Infof("Ran %v of %v iterations:", report.IterationsRun, report.IterationsRequested)
+	for _, test := range report.Tests {
+		logrus.Infof(
+			"- %v passed %v/%v runs (%.0f%%), taking %.1fs on average",
+			test.Name,
+			test.Passes,
+			test.Runs,
+			100*test.PassRate,
+			test.MeanDurationSeconds)
+		for _, failedRun := range test.FailedRuns {
+			logrus.Infof(
+				"    Iteration %v %v after %.1fs; reproduce with --test-names=%v --cert-seed=%v",
+				failedRun.Iteration,
+				failedRun.Status,
+				failedRun.DurationSeconds,
+				test.Name,
+				failedRun.CertSeed)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package poller
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+/*
+Determines how long a poller waits between consecutive observations
+*/
+type Backoff interface {
+	// Returns how long to wait before the next observation, given the number of observations made so far (starting at 1)
+	GetInterval(attempt int) time.Duration
+}
+
+// ================ Constant ====================
+// Waits the same amount of time between every observation
+type ConstantBackoff struct {
+	interval time.Duration
+}
+
+func NewConstantBackoff(interval time.Duration) *ConstantBackoff {
+	return &ConstantBackoff{interval: interval}
+}
+
+func (backoff ConstantBackoff) GetInterval(attempt int) time.Duration {
+	return backoff.interval
+}
+
+// ================ Exponential ====================
+// Multiplies the wait time by a constant factor after every observation, up to a maximum
+type ExponentialBackoff struct {
+	initialInterval time.Duration
+	multiplier      float64
+	maxInterval     time.Duration
+}
+
+/*
+Args:
+	initialInterval: The time to wait after the first observation
+	multiplier: The factor that the wait time grows by after each subsequent observation
+	maxInterval: The wait time will never grow past this value
+*/
+func NewExponentialBackoff(initialInterval time.Duration, multiplier float64, maxInterval time.Duration) *ExponentialBackoff {
+	return &ExponentialBackoff{
+		initialInterval: initialInterval,
+		multiplier:      multiplier,
+		maxInterval:     maxInterval,
+	}
+}
+
+func (backoff ExponentialBackoff) GetInterval(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	interval := float64(backoff.initialInterval) * math.Pow(backoff.multiplier, float64(attempt-1))
+	if interval > float64(backoff.maxInterval) {
+		return backoff.maxInterval
+	}
+	return time.Duration(interval)
+}
+
+// ================ Jittered ====================
+/*
+Randomly perturbs the wait times of another backoff, so that many pollers started at the same time don't all hit
+the network in lockstep
+*/
+type JitteredBackoff struct {
+	wrapped        Backoff
+	jitterFraction float64
+
+	// rand.Rand isn't threadsafe, so we need to guard it
+	randomMutex *sync.Mutex
+	random      *rand.Rand
+}
+
+/*
+Args:
+	wrapped: The backoff whose wait times will be jittered
+	jitterFraction: The maximum fraction (between 0 and 1) of the wrapped wait time to add or subtract, e.g. 0.2
+		will produce wait times between 80% and 120% of the wrapped backoff's
+*/
+func NewJitteredBackoff(wrapped Backoff, jitterFraction float64) *JitteredBackoff {
+	return &JitteredBackoff{
+		wrapped:        wrapped,
+		jitterFraction: jitterFraction,
+		randomMutex:    &sync.Mutex{},
+		random:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (backoff JitteredBackoff) GetInterval(attempt int) time.Duration {
+	interval := backoff.wrapped.GetInterval(attempt)
+
+	backoff.randomMutex.Lock()
+	// Uniformly distributed in [-1, 1)
+	jitterMultiplier := 2*backoff.random.Float64() - 1
+	backoff.randomMutex.Unlock()
+
+	jitteredInterval := float64(interval) * (1 + backoff.jitterFraction*jitterMultiplier)
+	if jitteredInterval < 0 {
+		return 0
+	}
+	return time.Duration(jitteredInterval)
+}
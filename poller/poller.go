@@ -0,0 +1,141 @@
+package poller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Retrieves the current value of whatever is being polled; an error aborts the polling entirely
+type Observer func() (interface{}, error)
+
+// A single value (or error) observed by a poller
+type Observation struct {
+	// Starts at 1
+	Attempt int
+	Time    time.Time
+	Value   interface{}
+	Err     error
+}
+
+/*
+Describes everything a poller observed before giving up, so that a failed wait can be debugged from the test logs
+*/
+type PollingFailureReport struct {
+	Description  string
+	StartTime    time.Time
+	EndTime      time.Time
+	Observations []Observation
+
+	// Why the poller gave up - either the observer's error or the context's error
+	Cause error
+}
+
+func (report PollingFailureReport) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(fmt.Sprintf(
+		"Polling for '%v' gave up after %v and %v observations: %v",
+		report.Description,
+		report.EndTime.Sub(report.StartTime),
+		len(report.Observations),
+		report.Cause))
+	for _, observation := range report.Observations {
+		elapsed := observation.Time.Sub(report.StartTime)
+		if observation.Err != nil {
+			builder.WriteString(fmt.Sprintf("\n  #%v (+%v): error: %v", observation.Attempt, elapsed, observation.Err))
+		} else {
+			builder.WriteString(fmt.Sprintf("\n  #%v (+%v): %+v", observation.Attempt, elapsed, observation.Value))
+		}
+	}
+	return builder.String()
+}
+
+// Returned when polling ends without the condition being satisfied
+type PollingError struct {
+	Report PollingFailureReport
+}
+
+func (err PollingError) Error() string {
+	return err.Report.String()
+}
+
+func (err PollingError) Unwrap() error {
+	return err.Report.Cause
+}
+
+/*
+Repeatedly observes some value until it satisfies a condition, the deadline is hit, or the observation fails
+*/
+type Poller struct {
+	description string
+	backoff     Backoff
+}
+
+/*
+Args:
+	description: Human-readable description of what's being waited for, used in failure reports
+	backoff: Determines how long to wait between observations
+*/
+func NewPoller(description string, backoff Backoff) *Poller {
+	return &Poller{
+		description: description,
+		backoff:     backoff,
+	}
+}
+
+/*
+Observes until the observed value satisfies the condition
+
+Args:
+	ctx: Context whose deadline or cancellation bounds the polling; a context without a deadline will poll forever
+	observer: Function retrieving the value to check
+	condition: Predicate the observed value must satisfy
+
+Returns:
+	The first observed value which satisfied the condition, or a PollingError carrying a report of all observations
+		if polling ended early
+*/
+func (poller Poller) PollUntil(ctx context.Context, observer Observer, condition Predicate) (interface{}, error) {
+	startTime := time.Now()
+	observations := []Observation{}
+	buildError := func(cause error) error {
+		return PollingError{
+			Report: PollingFailureReport{
+				Description:  poller.description,
+				StartTime:    startTime,
+				EndTime:      time.Now(),
+				Observations: observations,
+				Cause:        cause,
+			},
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, buildError(err)
+		}
+
+		value, err := observer()
+		observations = append(observations, Observation{
+			Attempt: attempt,
+			Time:    time.Now(),
+			Value:   value,
+			Err:     err,
+		})
+		if err != nil {
+			return nil, buildError(err)
+		}
+		if condition(value) {
+			return value, nil
+		}
+
+		timer := time.NewTimer(poller.backoff.GetInterval(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, buildError(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
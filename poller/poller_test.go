@@ -0,0 +1,121 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testInterval = time.Millisecond
+)
+
+func TestPollUntilSatisfied(t *testing.T) {
+	observationCount := 0
+	observer := func() (interface{}, error) {
+		observationCount++
+		return observationCount, nil
+	}
+
+	result, err := NewPoller("count to three", NewConstantBackoff(testInterval)).PollUntil(
+		context.Background(),
+		observer,
+		Equals(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result)
+	assert.Equal(t, 3, observationCount)
+}
+
+func TestPollUntilDeadlineReportsObservations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	observer := func() (interface{}, error) {
+		return "Processing", nil
+	}
+
+	_, err := NewPoller("transaction acceptance", NewConstantBackoff(10*time.Millisecond)).PollUntil(
+		ctx,
+		observer,
+		Equals("Accepted"))
+	var pollingErr PollingError
+	if !assert.True(t, errors.As(err, &pollingErr)) {
+		return
+	}
+	report := pollingErr.Report
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Equal(t, "transaction acceptance", report.Description)
+	assert.True(t, len(report.Observations) > 1)
+	for i, observation := range report.Observations {
+		assert.Equal(t, i+1, observation.Attempt)
+		assert.Equal(t, "Processing", observation.Value)
+	}
+	assert.True(t, strings.Contains(err.Error(), "Processing"))
+}
+
+func TestPollUntilObserverErrorAborts(t *testing.T) {
+	observerErr := errors.New("connection refused")
+	observationCount := 0
+	observer := func() (interface{}, error) {
+		observationCount++
+		if observationCount == 2 {
+			return nil, observerErr
+		}
+		return observationCount, nil
+	}
+
+	_, err := NewPoller("observer error", NewConstantBackoff(testInterval)).PollUntil(
+		context.Background(),
+		observer,
+		Equals(5))
+	assert.True(t, errors.Is(err, observerErr))
+	assert.Equal(t, 2, observationCount)
+	assert.Equal(t, 2, len(err.(PollingError).Report.Observations))
+}
+
+func TestPollUntilCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	observer := func() (interface{}, error) {
+		t.Fatal("Observer should not be called with a cancelled context")
+		return nil, nil
+	}
+
+	_, err := NewPoller("cancelled", NewConstantBackoff(testInterval)).PollUntil(ctx, observer, Equals(1))
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := NewExponentialBackoff(time.Second, 2, 5*time.Second)
+	assert.Equal(t, time.Second, backoff.GetInterval(1))
+	assert.Equal(t, 2*time.Second, backoff.GetInterval(2))
+	assert.Equal(t, 4*time.Second, backoff.GetInterval(3))
+	assert.Equal(t, 5*time.Second, backoff.GetInterval(4))
+	assert.Equal(t, 5*time.Second, backoff.GetInterval(100))
+}
+
+func TestJitteredBackoffStaysInBounds(t *testing.T) {
+	backoff := NewJitteredBackoff(NewConstantBackoff(time.Second), 0.2)
+	for i := 1; i <= 100; i++ {
+		interval := backoff.GetInterval(i)
+		assert.True(t, interval >= 800*time.Millisecond, "Interval %v was below the jitter bounds", interval)
+		assert.True(t, interval <= 1200*time.Millisecond, "Interval %v was above the jitter bounds", interval)
+	}
+}
+
+func TestPredicateComposition(t *testing.T) {
+	isPositive := func(value interface{}) bool { return value.(int) > 0 }
+	isEven := func(value interface{}) bool { return value.(int)%2 == 0 }
+
+	assert.True(t, And(isPositive, isEven)(2))
+	assert.False(t, And(isPositive, isEven)(3))
+	assert.True(t, Or(isPositive, isEven)(3))
+	assert.True(t, Or(isPositive, isEven)(-2))
+	assert.False(t, Or(isPositive, isEven)(-3))
+	assert.True(t, Not(isPositive)(-1))
+	assert.True(t, Not(Equals("0"))("100"))
+	assert.False(t, Not(Equals("0"))("0"))
+}
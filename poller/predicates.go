@@ -0,0 +1,44 @@
+package poller
+
+import "reflect"
+
+// A condition on an observed value that a poller waits to become true
+type Predicate func(value interface{}) bool
+
+// Returns a predicate that's true when the observed value is deeply equal to the expected value
+func Equals(expected interface{}) Predicate {
+	return func(value interface{}) bool {
+		return reflect.DeepEqual(expected, value)
+	}
+}
+
+// Returns a predicate that's true when all the given predicates are true
+func And(predicates ...Predicate) Predicate {
+	return func(value interface{}) bool {
+		for _, predicate := range predicates {
+			if !predicate(value) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Returns a predicate that's true when any of the given predicates is true
+func Or(predicates ...Predicate) Predicate {
+	return func(value interface{}) bool {
+		for _, predicate := range predicates {
+			if predicate(value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns a predicate that's true when the given predicate is false
+func Not(predicate Predicate) Predicate {
+	return func(value interface{}) bool {
+		return !predicate(value)
+	}
+}
@@ -12,7 +12,7 @@ MAIN_BINARY_OUTPUT_PATH="${ROOT_DIRPATH}/${BUILD_DIR}/${MAIN_BINARY_OUTPUT_FILE}
 echo "Running unit tests..."
 go test "${ROOT_DIRPATH}"/...
 echo "Building..."
-go build -o "${MAIN_BINARY_OUTPUT_PATH}" "${ROOT_DIRPATH}/initializer/main.go"
+go build -o "${MAIN_BINARY_OUTPUT_PATH}" "${ROOT_DIRPATH}/initializer"
 EXIT_STATUS=$?
 
 if [ "${EXIT_STATUS}" -eq "0" ]; then
@@ -5,4 +5,4 @@ GECKO_IMAGE_DEFAULT="kurtosistech/gecko"    # TODO change this to be a Gecko ima
 CONTROLLER_IMAGE="kurtosistech/ava-e2e-tests_controller:latest"     # TODO Change this Docker org to be Ava labs
 root_dirpath="$(dirname "${script_dirpath}")"
 
-"${root_dirpath}/build/ava-e2e-tests" "--gecko-image-name=${GECKO_IMAGE_DEFAULT}" "--test-controller-image-name=${CONTROLLER_IMAGE}" ${*:-}
+"${root_dirpath}/build/ava-e2e-tests" "--gecko-image-name=${GECKO_IMAGE_DEFAULT}" "--test-controller-image-name=${CONTROLLER_IMAGE}" "--network-specs-dirpath=${root_dirpath}/network_specs" ${*:-}